@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/auth"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/util"
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+// authExemptMethods are served to unauthenticated callers, for orchestrators
+// to probe the controller
+var authExemptMethods = []string{
+	"/vdp.controller.v1alpha.ControllerPrivateService/Liveness",
+	"/vdp.controller.v1alpha.ControllerPrivateService/Readiness",
+	"/grpc.health.v1.Health/*",
+}
+
+type authenticator struct {
+	keySet     *auth.KeySet
+	authorizer *auth.Authorizer
+}
+
+// newAuthInterceptors returns the interceptors authenticating the callers with
+// their client certificate or JWT, and authorizing their calls
+func newAuthInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor, error) {
+	a := &authenticator{
+		authorizer: auth.NewAuthorizer(config.Config.Auth.Rules),
+	}
+
+	if config.Config.Auth.JWT.JWKSFile != "" {
+		keySet, err := auth.LoadKeySet(config.Config.Auth.JWT.JWKSFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		a.keySet = keySet
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.check(ctx, info.FullMethod, requestResourceType(req)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.check(ss.Context(), info.FullMethod, ""); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream, nil
+}
+
+// check authenticates and authorizes a call, writing an audit log entry for
+// the denied ones
+func (a *authenticator) check(ctx context.Context, fullMethod string, resourceType string) error {
+	for _, pattern := range authExemptMethods {
+		if ok, _ := path.Match(pattern, fullMethod); ok {
+			return nil
+		}
+	}
+
+	principal, err := a.authenticate(ctx)
+	if err != nil {
+		audit(ctx, fullMethod, principal, err)
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if err := a.authorizer.Authorize(principal, fullMethod, resourceType); err != nil {
+		audit(ctx, fullMethod, principal, err)
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return nil
+}
+
+// authenticate returns the identity of a caller, the subject of its verified
+// client certificate or else of the bearer token it sent
+func (a *authenticator) authenticate(ctx context.Context) (auth.Principal, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			return auth.Principal{Name: tlsInfo.State.VerifiedChains[0][0].Subject.CommonName, Mechanism: "mtls"}, nil
+		}
+	}
+
+	if a.keySet == nil {
+		return auth.Principal{}, fmt.Errorf("client certificate required")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return auth.Principal{}, fmt.Errorf("client certificate or bearer token required")
+	}
+	if !strings.HasPrefix(values[0], "Bearer ") {
+		return auth.Principal{}, fmt.Errorf("authorization is not a bearer token")
+	}
+	token := strings.TrimPrefix(values[0], "Bearer ")
+
+	claims, err := a.keySet.Verify(token, config.Config.Auth.JWT.Issuer, config.Config.Auth.JWT.Audience, time.Now())
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	return auth.Principal{Name: claims.Subject, Mechanism: "jwt"}, nil
+}
+
+// requestResourceType returns the type of the resource a request is about,
+// empty if none or invalid, the handler rejecting invalid permalinks
+func requestResourceType(req interface{}) string {
+	var permalink string
+	switch r := req.(type) {
+	case *controllerPB.GetResourceRequest:
+		permalink = r.ResourcePermalink
+	case *controllerPB.UpdateResourceRequest:
+		permalink = r.GetResource().GetResourcePermalink()
+	case *controllerPB.DeleteResourceRequest:
+		permalink = r.ResourcePermalink
+	default:
+		return ""
+	}
+
+	p, err := util.ParseResourcePermalink(permalink)
+	if err != nil {
+		return ""
+	}
+	return p.Type
+}
+
+func audit(ctx context.Context, fullMethod string, principal auth.Principal, reason error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	address := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		address = p.Addr.String()
+	}
+
+	logger.Warn(fmt.Sprintf("[Audit] denied %s to %s from %s: %v", fullMethod, principal, address, reason))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/instill-ai/controller/config"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+)
+
+// controllerServer serves the methods reachable in the tests
+type controllerServer struct {
+	controllerPB.UnimplementedControllerPrivateServiceServer
+}
+
+func (controllerServer) Liveness(ctx context.Context, req *controllerPB.LivenessRequest) (*controllerPB.LivenessResponse, error) {
+	return &controllerPB.LivenessResponse{HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
+		Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
+	}}, nil
+}
+
+func (controllerServer) GetSystemHealth(ctx context.Context, req *controllerPB.GetSystemHealthRequest) (*controllerPB.GetSystemHealthResponse, error) {
+	return &controllerPB.GetSystemHealthResponse{Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING}, nil
+}
+
+func TestGatewayAuthentication(t *testing.T) {
+	authConfig := config.Config.Auth
+	t.Cleanup(func() {
+		config.Config.Auth = authConfig
+	})
+	config.Config.Auth = config.AuthConfig{Enabled: true}
+
+	unaryAuth, streamAuth, err := newAuthInterceptors()
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcS := grpc.NewServer(grpc.UnaryInterceptor(unaryAuth), grpc.StreamInterceptor(streamAuth))
+	controllerPB.RegisterControllerPrivateServiceServer(grpcS, controllerServer{})
+	go func() {
+		_ = grpcS.Serve(listener)
+	}()
+	defer grpcS.Stop()
+
+	// the gateway dials the gRPC server as in main
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	serverMux := newServeMux()
+	require.NoError(t, controllerPB.RegisterControllerPrivateServiceHandler(context.Background(), serverMux, conn))
+
+	for _, tc := range []struct {
+		name string
+		path string
+		code int
+	}{
+		{name: "system health denied", path: "/v1alpha/health/system", code: http.StatusUnauthorized},
+		{name: "resource denied", path: "/v1alpha/resources/model-backend/types/services", code: http.StatusUnauthorized},
+		{name: "liveness exempt", path: "/v1alpha/health/controller", code: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			serverMux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.code == http.StatusUnauthorized {
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -2,18 +2,21 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -22,9 +25,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/protobuf/encoding/protojson"
+
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
@@ -32,12 +35,12 @@ import (
 
 	"github.com/instill-ai/controller/config"
 	"github.com/instill-ai/controller/internal/external"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
 	"github.com/instill-ai/controller/pkg/handler"
 	"github.com/instill-ai/controller/pkg/logger"
 	"github.com/instill-ai/controller/pkg/service"
 
 	custom_otel "github.com/instill-ai/controller/pkg/logger/otel"
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 )
 
 var propagator propagation.TextMapPropagator
@@ -67,20 +70,14 @@ func main() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	if tp, err := custom_otel.SetupTracing(ctx, "controller"); err != nil {
+	tp, err := custom_otel.SetupTracing(ctx, "controller")
+	if err != nil {
 		panic(err)
-	} else {
-		defer func() {
-			err = tp.Shutdown(ctx)
-		}()
 	}
 
-	if mp, err := custom_otel.SetupMetrics(ctx, "controller"); err != nil {
+	mp, err := custom_otel.SetupMetrics(ctx, "controller")
+	if err != nil {
 		panic(err)
-	} else {
-		defer func() {
-			err = mp.Shutdown(ctx)
-		}()
 	}
 
 	ctx, span := otel.Tracer("main-tracer").Start(ctx,
@@ -95,11 +92,15 @@ func main() {
 	}()
 	grpc_zap.ReplaceGrpcLoggerV2(logger)
 
-	// Create tls based credential.
-	var creds credentials.TransportCredentials
-	var err error
+	// Create tls based credential, reloading the certificate files when they
+	// change on disk
+	var serverTLSConfig *tls.Config
 	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key)
+		clientCA := ""
+		if config.Config.Auth.Enabled {
+			clientCA = config.Config.Auth.ClientCA
+		}
+		serverTLSConfig, err = external.NewServerTLSConfig(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key, clientCA)
 		if err != nil {
 			logger.Fatal(fmt.Sprintf("failed to create credentials: %v", err))
 		}
@@ -113,57 +114,90 @@ func main() {
 				if match, _ := regexp.MatchString("vdp.model.v1alpha.ModelPublicService/.*ness$", fullMethodName); match {
 					return false
 				}
+				if match, _ := regexp.MatchString("vdp.controller.v1alpha.ControllerPrivateService/.*ness$", fullMethodName); match {
+					return false
+				}
+				if match, _ := regexp.MatchString("grpc.health.v1.Health/.*$", fullMethodName); match {
+					return false
+				}
 			}
 			// by default everything will be logged
 			return true
 		}),
 	}
 
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_zap.StreamServerInterceptor(logger, opts...),
+		grpc_recovery.StreamServerInterceptor(recoveryInterceptorOpt()),
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_zap.UnaryServerInterceptor(logger, opts...),
+		grpc_recovery.UnaryServerInterceptor(recoveryInterceptorOpt()),
+	}
+	if config.Config.Auth.Enabled {
+		unaryAuth, streamAuth, err := newAuthInterceptors()
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("failed to set up authentication: %v", err))
+		}
+		streamInterceptors = append(streamInterceptors, streamAuth)
+		unaryInterceptors = append(unaryInterceptors, unaryAuth)
+	}
+
 	grpcServerOpts := []grpc.ServerOption{
-		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			grpc_zap.StreamServerInterceptor(logger, opts...),
-			grpc_recovery.StreamServerInterceptor(recoveryInterceptorOpt()),
-		)),
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_zap.UnaryServerInterceptor(logger, opts...),
-			grpc_recovery.UnaryServerInterceptor(recoveryInterceptorOpt()),
-		)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(streamInterceptors...)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
 	}
 
-	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
-		grpcServerOpts = append(grpcServerOpts, grpc.Creds(creds))
+	if serverTLSConfig != nil {
+		grpcServerOpts = append(grpcServerOpts, grpc.Creds(credentials.NewTLS(serverTLSConfig)))
 	}
 
 	grpcS := grpc.NewServer(grpcServerOpts...)
 	reflection.Register(grpcS)
 
-	mgmtPublicServiceClient, mgmtPublicServiceClientConn := external.InitMgmtPublicServiceClient(ctx)
-	defer mgmtPublicServiceClientConn.Close()
-
-	pipelinePublicServiceClient, pipelinePublicServiceClientConn := external.InitPipelinePublicServiceClient(ctx)
-	defer pipelinePublicServiceClientConn.Close()
-
-	pipelinePrivateServiceClient, pipelinePrivateServiceClientConn := external.InitPipelinePrivateServiceClient(ctx)
-	defer pipelinePrivateServiceClientConn.Close()
-
-	modelPublicServiceClient, modelPublicServiceClientConn := external.InitModelPublicServiceClient(ctx)
-	defer modelPublicServiceClientConn.Close()
+	// the backend clients connect on first use and reconnect by themselves,
+	// a backend being down at startup only makes it reported as unavailable
+	clients := external.NewClientManager(ctx)
+	defer clients.Close()
 
-	modelPrivateServiceClient, modelPrivateServiceClientConn := external.InitModelPrivateServiceClient(ctx)
-	defer modelPrivateServiceClientConn.Close()
-
-	connectorPublicServiceClient, connectorPublicServiceClientConn := external.InitConnectorPublicServiceClient(ctx)
-	defer connectorPublicServiceClientConn.Close()
-
-	connectorPrivateServiceClient, connectorPrivateServiceClientConn := external.InitConnectorPrivateServiceClient(ctx)
-	defer connectorPrivateServiceClientConn.Close()
+	// only a configuration error, e.g. an invalid TLS setting, fails the
+	// setup of a client
+	mgmtPublicServiceClient, err := external.InitMgmtPublicServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	pipelinePublicServiceClient, err := external.InitPipelinePublicServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	pipelinePrivateServiceClient, err := external.InitPipelinePrivateServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	modelPublicServiceClient, err := external.InitModelPublicServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	modelPrivateServiceClient, err := external.InitModelPrivateServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	connectorPublicServiceClient, err := external.InitConnectorPublicServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	connectorPrivateServiceClient, err := external.InitConnectorPrivateServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	tritonClient, err := external.InitTritonServiceClient(ctx, clients)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
 
 	etcdClient := external.InitEtcdServiceClient(ctx)
 	defer etcdClient.Close()
 
-	tritonClient, tritonClientConn := external.InitTritonServiceClient(ctx)
-	defer tritonClientConn.Close()
-
 	service := service.NewService(
 		*etcdClient,
 		tritonClient,
@@ -174,6 +208,7 @@ func main() {
 		pipelinePrivateServiceClient,
 		connectorPublicServiceClient,
 		connectorPrivateServiceClient,
+		clients,
 	)
 
 	controllerPB.RegisterControllerPrivateServiceServer(
@@ -182,44 +217,59 @@ func main() {
 		),
 	)
 
-	serverMux := runtime.NewServeMux(
-		runtime.WithForwardResponseOption(httpResponseModifier),
-		runtime.WithErrorHandler(errorHandler),
-		runtime.WithIncomingHeaderMatcher(customMatcher),
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions:   protojson.MarshalOptions{},
-			UnmarshalOptions: protojson.UnmarshalOptions{},
-		}),
+	healthgrpc.RegisterHealthServer(
+		grpcS, handler.NewHealthServer(
+			ctx,
+			service,
+			config.Config.Server.LoopInterval*time.Second,
+		),
 	)
 
-	var dialOpts []grpc.DialOption
-	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
-		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(creds)}
-	} else {
-		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	serverMux := newServeMux()
+
+	// the gateway dials the server it runs in, presenting no client
+	// certificate so that HTTP callers authenticate with their own token
+	gatewayDialOpt, err := external.NewClientDialOption(gatewayTLSConfig(serverTLSConfig != nil))
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to create the gateway credentials: %v", err))
+	}
+	if serverTLSConfig != nil && config.Config.Server.HTTPS.InsecureSkipVerify {
+		logger.Warn("the gateway does not verify the server certificate, only meant for development")
 	}
+	dialOpts := []grpc.DialOption{gatewayDialOpt}
 
 	if err := controllerPB.RegisterControllerPrivateServiceHandlerFromEndpoint(ctx, serverMux, fmt.Sprintf(":%v", config.Config.Server.Port), dialOpts); err != nil {
 		logger.Fatal(err.Error())
 	}
 
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%v", config.Config.Server.Port),
-		Handler: grpcHandlerFunc(grpcS, serverMux),
+		Addr:      fmt.Sprintf(":%v", config.Config.Server.Port),
+		Handler:   grpcHandlerFunc(grpcS, serverMux),
+		TLSConfig: serverTLSConfig,
+	}
+
+	// the listener is bound before serving, so that the gateway and the
+	// readiness probes can reach the server as soon as the control loop starts
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("failed to listen on %s: %v", httpServer.Addr, err))
 	}
 
 	// Wait for interrupt signal to gracefully shutdown the server with a timeout of 5 seconds.
 	quitSig := make(chan os.Signal, 1)
-	errSig := make(chan error)
-	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
+	// the server error is buffered so that serving stops even once nothing
+	// receives it, shutting down returns http.ErrServerClosed which is no error
+	errSig := make(chan error, 1)
+	if serverTLSConfig != nil {
 		go func() {
-			if err := httpServer.ListenAndServeTLS(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key); err != nil {
+			// the certificate is served from the TLS config
+			if err := httpServer.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				errSig <- err
 			}
 		}()
 	} else {
 		go func() {
-			if err := httpServer.ListenAndServe(); err != nil {
+			if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				errSig <- err
 			}
 		}()
@@ -227,31 +277,65 @@ func main() {
 	span.End()
 	logger.Info("gRPC server is running.")
 
-	// Workaround, wait the http server ready
-	time.Sleep(10 * time.Second)
+	// loopCtx stops the scheduling of probe cycles, and probeCtx cancels the
+	// probes in flight once the drain deadline is over
+	loopCtx, stopLoop := context.WithCancel(ctx)
+	probeCtx, cancelProbes := context.WithCancel(ctx)
+	loopDone := make(chan struct{})
 
+	// keep the resource snapshot up to date, the reads going to etcd while
+	// the watch restarts
 	go func() {
+		for {
+			err := service.WatchResources(loopCtx)
+			if loopCtx.Err() != nil {
+				return
+			}
+			logger.Warn(fmt.Sprintf("[controller] resource watch stopped, restarting: %v", err))
+			time.Sleep(time.Second)
+		}
+	}()
+
+	go func() {
+		defer close(loopDone)
+
 		// repopulate connector resource
 		isRepopulate := false
 
+		// Readiness reports the startup phase until the control loop can
+		// safely start probing
+		if err := service.WaitForStartup(loopCtx); err != nil {
+			logger.Info(fmt.Sprintf("[controller] startup interrupted: %v", err))
+			return
+		}
+
 		logger.Info("[controller] control loop started")
 		var mainWG sync.WaitGroup
-		for {
+		for loopCtx.Err() == nil {
 			logger.Info("[controller] --------------Start probing------------")
 
-			for etcdClient.ActiveConnection().GetState() != connectivity.Ready {
+			for etcdClient.ActiveConnection().GetState() != connectivity.Ready && loopCtx.Err() == nil {
 				logger.Warn("[controller] etcd connection lost, waiting for state change...")
-				etcdClient.ActiveConnection().WaitForStateChange(ctx, connectivity.TransientFailure)
+				etcdClient.ActiveConnection().WaitForStateChange(loopCtx, connectivity.TransientFailure)
 				time.Sleep(50 * time.Millisecond)
 				isRepopulate = false
+				service.SetPopulated(false)
 			}
+			if loopCtx.Err() != nil {
+				break
+			}
+
+			repopulating := !isRepopulate
 
 			mainWG.Add(3)
 
+			var probeFailed atomic.Bool
+
 			// Backend services
 			go func() {
 				defer mainWG.Done()
-				if err := service.ProbeBackend(context.WithTimeout(ctx, config.Config.Server.Timeout*time.Second)); err != nil {
+				if err := service.ProbeBackend(context.WithTimeout(probeCtx, config.Config.Server.Timeout*time.Second)); err != nil {
+					probeFailed.Store(true)
 					logger.Error(err.Error())
 				}
 			}()
@@ -259,7 +343,8 @@ func main() {
 			// Models
 			go func() {
 				defer mainWG.Done()
-				if err := service.ProbeModels(context.WithTimeout(ctx, config.Config.Server.Timeout*time.Second)); err != nil {
+				if err := service.ProbeModels(context.WithTimeout(probeCtx, config.Config.Server.Timeout*time.Second)); err != nil {
+					probeFailed.Store(true)
 					logger.Error(err.Error())
 				}
 			}()
@@ -271,13 +356,15 @@ func main() {
 				mainWG.Add(2)
 				go func() {
 					defer mainWG.Done()
-					if err := service.ProbeSourceConnectors(context.WithTimeout(ctx, config.Config.Server.Timeout*time.Second)); err != nil {
+					if err := service.ProbeSourceConnectors(context.WithTimeout(probeCtx, config.Config.Server.Timeout*time.Second)); err != nil {
+						probeFailed.Store(true)
 						logger.Error(err.Error())
 					}
 				}()
 				go func() {
 					defer mainWG.Done()
-					if err := service.ProbeDestinationConnectors(context.WithTimeout(ctx, config.Config.Server.Timeout*time.Second)); err != nil {
+					if err := service.ProbeDestinationConnectors(context.WithTimeout(probeCtx, config.Config.Server.Timeout*time.Second)); err != nil {
+						probeFailed.Store(true)
 						logger.Error(err.Error())
 					}
 				}()
@@ -287,14 +374,30 @@ func main() {
 			// Pipelines
 			go func() {
 				defer mainWG.Done()
-				if err := service.ProbePipelines(context.WithTimeout(ctx, config.Config.Server.Timeout*time.Second)); err != nil {
+				if err := service.ProbePipelines(context.WithTimeout(probeCtx, config.Config.Server.Timeout*time.Second)); err != nil {
+					probeFailed.Store(true)
 					logger.Error(err.Error())
 				}
 			}()
 
-			time.Sleep(config.Config.Server.LoopInterval * time.Second)
+			select {
+			case <-time.After(config.Config.Server.LoopInterval * time.Second):
+			case <-loopCtx.Done():
+			}
 			mainWG.Wait()
+
+			switch {
+			case !probeFailed.Load():
+				service.RecordProbeCycle(time.Now())
+				if repopulating {
+					service.SetPopulated(true)
+				}
+			case repopulating:
+				// retry the repopulation on the next cycle
+				isRepopulate = false
+			}
 		}
+		logger.Info("[controller] control loop stopped")
 	}()
 
 	// kill (no param) default send syscall.SIGTERM
@@ -307,7 +410,41 @@ func main() {
 		logger.Error(fmt.Sprintf("Fatal error: %v\n", err))
 	case <-quitSig:
 		logger.Info("Shutting down server...")
-		grpcS.GracefulStop()
 	}
 
+	// no new probe cycle is scheduled, the probes in flight are given until
+	// the drain deadline to complete before their contexts are cancelled
+	stopLoop()
+	select {
+	case <-loopDone:
+	case <-time.After(config.Config.Server.ShutdownTimeout * time.Second):
+		logger.Warn("[controller] probes still running after the drain deadline, cancelling them")
+		cancelProbes()
+		<-loopDone
+	}
+	cancelProbes()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), config.Config.Server.ShutdownTimeout*time.Second)
+	defer cancelShutdown()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error(fmt.Sprintf("failed to shut down the HTTP server: %v", err))
+	}
+	grpcS.GracefulStop()
+
+	// the state transitions notified before the server stopped are delivered
+	if err := service.Close(shutdownCtx); err != nil {
+		logger.Error(fmt.Sprintf("failed to deliver the pending notifications: %v", err))
+	}
+
+	// the telemetry of the shutdown itself is flushed too
+	if err := tp.Shutdown(shutdownCtx); err != nil {
+		logger.Error(fmt.Sprintf("failed to flush the traces: %v", err))
+	}
+	if err := mp.Shutdown(shutdownCtx); err != nil {
+		logger.Error(fmt.Sprintf("failed to flush the metrics: %v", err))
+	}
+
+	// the clients are closed by the deferred calls
+	logger.Info("Server stopped")
 }
@@ -10,13 +10,53 @@ import (
 	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/instill-ai/controller/config"
 	"github.com/instill-ai/controller/pkg/logger"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
+// newServeMux returns the HTTP gateway of the controller API, its routes being
+// registered to dial the gRPC server for the calls to go through the same
+// interceptors as gRPC ones
+func newServeMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithForwardResponseOption(httpResponseModifier),
+		runtime.WithErrorHandler(errorHandler),
+		runtime.WithIncomingHeaderMatcher(customMatcher),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{},
+			UnmarshalOptions: protojson.UnmarshalOptions{},
+		}),
+	)
+}
+
+// gatewayTLSConfig returns the TLS settings of the gateway dialing the server
+// it runs in, verifying the server certificate against the configured CA
+// bundle or else the certificate itself
+func gatewayTLSConfig(enabled bool) config.TLSConfig {
+	https := config.Config.Server.HTTPS
+
+	caCert := https.CACert
+	if caCert == "" {
+		caCert = https.Cert
+	}
+	serverName := https.ServerName
+	if serverName == "" {
+		serverName = "localhost"
+	}
+
+	return config.TLSConfig{
+		Enabled:            enabled,
+		CACert:             caCert,
+		ServerName:         serverName,
+		InsecureSkipVerify: https.InsecureSkipVerify,
+	}
+}
+
 func httpResponseModifier(ctx context.Context, w http.ResponseWriter, p proto.Message) error {
 	md, ok := runtime.ServerMetadataFromContext(ctx)
 	if !ok {
@@ -129,6 +169,11 @@ func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.
 		} else {
 			httpStatus = runtime.HTTPStatusFromCode(s.Code())
 		}
+	case s.Code() == codes.Unavailable, s.Code() == codes.Aborted:
+		// the request can be retried as is once etcd is back or the
+		// conflicting write is done
+		w.Header().Set("Retry-After", "1")
+		httpStatus = runtime.HTTPStatusFromCode(s.Code())
 	default:
 		httpStatus = runtime.HTTPStatusFromCode(s.Code())
 	}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/external"
+)
+
+// writeServerCertificate writes a self-signed certificate of localhost and its
+// key, returning their paths
+func writeServerCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "controller"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestGatewayTLSConfig(t *testing.T) {
+	certFile, keyFile := writeServerCertificate(t)
+
+	serverTLSConfig, err := external.NewServerTLSConfig(certFile, keyFile, "")
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = serverTLSConfig
+	server.StartTLS()
+	defer server.Close()
+
+	httpsConfig := config.Config.Server.HTTPS
+	t.Cleanup(func() {
+		config.Config.Server.HTTPS = httpsConfig
+	})
+
+	for _, tc := range []struct {
+		name               string
+		serverName         string
+		insecureSkipVerify bool
+		verified           bool
+	}{
+		{name: "server certificate trusted", verified: true},
+		{name: "server name mismatch", serverName: "controller.example.com"},
+		{name: "verification skipped", serverName: "controller.example.com", insecureSkipVerify: true, verified: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config.Config.Server.HTTPS.Cert = certFile
+			config.Config.Server.HTTPS.Key = keyFile
+			config.Config.Server.HTTPS.ServerName = tc.serverName
+			config.Config.Server.HTTPS.InsecureSkipVerify = tc.insecureSkipVerify
+
+			tlsConfig, err := external.NewClientTLSConfig(gatewayTLSConfig(true))
+			require.NoError(t, err)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+			resp, err := client.Get(server.URL)
+			if tc.verified {
+				require.NoError(t, err)
+				resp.Body.Close()
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
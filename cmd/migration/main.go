@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/external"
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+// resourcesPrefix is the prefix of every key written by the controller
+const resourcesPrefix = "resources/"
+
+// main moves the keys written without namespace under the configured etcd
+// namespace, to be run while no controller of the installation is running
+func main() {
+	dryRun := flag.Bool("dry-run", false, "list the keys to move without moving them")
+
+	if err := config.Init(); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger, _ := logger.GetZapLogger(ctx)
+	defer func() {
+		// can't handle the error due to https://github.com/uber-go/zap/issues/880
+		_ = logger.Sync()
+	}()
+
+	namespace := config.Config.Etcd.Namespace
+	if namespace == "" {
+		logger.Info("[Migration] no etcd namespace configured, nothing to migrate")
+		return
+	}
+
+	client := external.InitEtcdRootServiceClient(ctx)
+	defer client.Close()
+
+	if _, err := migrate(ctx, client.KV, namespace, *dryRun); err != nil {
+		logger.Fatal(fmt.Sprintf("[Migration] %v", err))
+	}
+}
+
+// migrate moves the keys written without namespace under the namespace,
+// returning the number of keys moved, none if dryRun is set
+func migrate(ctx context.Context, client etcdv3.KV, namespace string, dryRun bool) (int, error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp, err := client.Get(ctx, resourcesPrefix, etcdv3.WithPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the keys: %w", err)
+	}
+
+	moved, skipped := 0, 0
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		target := namespace + key
+
+		if dryRun {
+			logger.Info(fmt.Sprintf("[Migration] would move %s to %s", key, target))
+			continue
+		}
+
+		// the key is only moved if unchanged since listed and not already
+		// written under the namespace
+		txnResp, err := client.Txn(ctx).
+			If(
+				etcdv3.Compare(etcdv3.ModRevision(key), "=", kv.ModRevision),
+				etcdv3.Compare(etcdv3.CreateRevision(target), "=", 0),
+			).
+			Then(
+				etcdv3.OpPut(target, string(kv.Value)),
+				etcdv3.OpDelete(key),
+			).
+			Commit()
+		if err != nil {
+			return moved, fmt.Errorf("failed to move %s: %w", key, err)
+		}
+
+		if !txnResp.Succeeded {
+			logger.Warn(fmt.Sprintf("[Migration] skipped %s, changed meanwhile or already present as %s", key, target))
+			skipped++
+			continue
+		}
+		moved++
+	}
+
+	logger.Info(fmt.Sprintf("[Migration] moved %d keys under %s, skipped %d of %d", moved, namespace, skipped, len(resp.Kvs)))
+
+	return moved, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+)
+
+const namespace = "instill/"
+
+// listedKeys are the keys written without namespace
+var listedKeys = []*mvccpb.KeyValue{
+	{Key: []byte("resources/a/types/models"), Value: []byte("2"), ModRevision: 4},
+	{Key: []byte("resources/b/types/pipelines"), Value: []byte("3"), ModRevision: 7},
+}
+
+// expectMove expects a key to be moved under the namespace if unchanged since
+// listed and absent from the namespace, the transaction succeeding or not
+func expectMove(ctrl *gomock.Controller, mockKV *MockKV, kv *mvccpb.KeyValue, succeeded bool, err error) {
+	key, target := string(kv.Key), namespace+string(kv.Key)
+
+	mockTxn := NewMockTxn(ctrl)
+	mockKV.
+		EXPECT().
+		Txn(gomock.Any()).
+		Return(mockTxn).
+		Times(1)
+	mockTxn.
+		EXPECT().
+		If(
+			etcdv3.Compare(etcdv3.ModRevision(key), "=", kv.ModRevision),
+			etcdv3.Compare(etcdv3.CreateRevision(target), "=", 0),
+		).
+		Return(mockTxn).
+		Times(1)
+	mockTxn.
+		EXPECT().
+		Then(etcdv3.OpPut(target, string(kv.Value)), etcdv3.OpDelete(key)).
+		Return(mockTxn).
+		Times(1)
+	mockTxn.
+		EXPECT().
+		Commit().
+		Return(&etcdv3.TxnResponse{Succeeded: succeeded}, err).
+		Times(1)
+}
+
+func TestMigrate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expectList := func(mockKV *MockKV) {
+		mockKV.
+			EXPECT().
+			Get(ctx, resourcesPrefix, gomock.Any()).
+			Return(&etcdv3.GetResponse{Kvs: listedKeys}, nil).
+			Times(1)
+	}
+
+	t.Run("keys moved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		expectList(mockKV)
+		for _, kv := range listedKeys {
+			expectMove(ctrl, mockKV, kv, true, nil)
+		}
+
+		moved, err := migrate(ctx, mockKV, namespace, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, moved)
+	})
+
+	t.Run("target exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		// the first key is already under the namespace, or changed since
+		// listed, and left as is
+		expectList(mockKV)
+		expectMove(ctrl, mockKV, listedKeys[0], false, nil)
+		expectMove(ctrl, mockKV, listedKeys[1], true, nil)
+
+		moved, err := migrate(ctx, mockKV, namespace, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, moved)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		// nothing is written
+		expectList(mockKV)
+
+		moved, err := migrate(ctx, mockKV, namespace, true)
+		require.NoError(t, err)
+		assert.Zero(t, moved)
+	})
+
+	t.Run("move failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		// the migration stops at the first failure, to be run again
+		expectList(mockKV)
+		expectMove(ctrl, mockKV, listedKeys[0], false, errors.New("etcdserver: request timed out"))
+
+		moved, err := migrate(ctx, mockKV, namespace, false)
+		assert.ErrorContains(t, err, "resources/a/types/models")
+		assert.Zero(t, moved)
+	})
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"strings"
@@ -12,11 +13,35 @@ import (
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/redis/go-redis/v9"
+
+	"github.com/instill-ai/controller/internal/util"
 )
 
 // Config - Global variable to export
 var Config AppConfig
 
+// Probe kinds supported for a backend service
+const (
+	// ProbeKindGRPCHealth probes the standard grpc.health.v1.Health service
+	ProbeKindGRPCHealth = "grpc-health"
+	// ProbeKindLiveness probes the Instill Liveness RPC of a backend
+	ProbeKindLiveness = "liveness"
+	// ProbeKindTriton probes the Triton ServerLive RPC
+	ProbeKindTriton = "triton"
+	// ProbeKindHTTP probes an HTTP endpoint with a GET request
+	ProbeKindHTTP = "http"
+	// ProbeKindTCP probes an address by opening a TCP connection
+	ProbeKindTCP = "tcp"
+)
+
+// Publisher kinds supported for state transition events
+const (
+	// PublisherKindNoop publishes nothing
+	PublisherKindNoop = "noop"
+	// PublisherKindRedisStreams appends events to a Redis stream
+	PublisherKindRedisStreams = "redis-streams"
+)
+
 // AppConfig defines
 type AppConfig struct {
 	Server           ServerConfig           `koanf:"server"`
@@ -28,27 +53,63 @@ type AppConfig struct {
 	ModelBackend     ModelBackendConfig     `koanf:"modelbackend"`
 	PipelineBackend  PipelineBackendConfig  `koanf:"pipelinebackend"`
 	MgmtBackend      MgmtBackendConfig      `koanf:"mgmtbackend"`
+	BackendServices  []BackendServiceConfig `koanf:"backendservices"`
+	Notifier         NotifierConfig         `koanf:"notifier"`
+	Auth             AuthConfig             `koanf:"auth"`
 	Log              LogConfig              `koanf:"log"`
 }
 
 // ServerConfig defines HTTP server configurations
 type ServerConfig struct {
-	Port  int `koanf:"privateport"`
+	Port int `koanf:"privateport"`
+	// HTTPS is the certificate served, the gateway dialing the server it
+	// runs in trusts the CA bundle if set and the certificate itself
+	// otherwise, for the given server name, localhost by default.
+	// InsecureSkipVerify is only meant for development
 	HTTPS struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
+		Cert               string `koanf:"cert"`
+		Key                string `koanf:"key"`
+		CACert             string `koanf:"cacert"`
+		ServerName         string `koanf:"servername"`
+		InsecureSkipVerify bool   `koanf:"insecureskipverify"`
+	}
+	Edition             string        `koanf:"edition"`
+	Debug               bool          `koanf:"debug"`
+	LoopInterval        time.Duration `koanf:"loopinterval"`
+	Timeout             time.Duration `koanf:"timeout"`
+	ProbeStaleThreshold time.Duration `koanf:"probestalethreshold"`
+	PageSize            int64         `koanf:"pagesize"`
+	FullSweepInterval   time.Duration `koanf:"fullsweepinterval"`
+	ProbeConcurrency    int           `koanf:"probeconcurrency"`
+	ProbeCheckTimeout   time.Duration `koanf:"probechecktimeout"`
+	ShutdownTimeout     time.Duration `koanf:"shutdowntimeout"`
+	CircuitBreaker      struct {
+		Threshold int           `koanf:"threshold"`
+		Cooldown  time.Duration `koanf:"cooldown"`
+	}
+	// Startup gates the control loop on the backends it cannot probe
+	// resources without, retried until the timeout before starting anyway
+	Startup struct {
+		RequiredBackends []string      `koanf:"requiredbackends"`
+		Timeout          time.Duration `koanf:"timeout"`
 	}
-	Edition      string        `koanf:"edition"`
-	Debug        bool          `koanf:"debug"`
-	LoopInterval time.Duration `koanf:"loopinterval"`
-	Timeout      time.Duration `koanf:"timeout"`
 }
 
-// EtcdConfig
+// EtcdConfig related to the etcd cluster resource states are stored in, the
+// keys being prefixed with the namespace so that installations can share it
 type EtcdConfig struct {
-	Host    string        `koanf:"host"`
-	Port    string        `koanf:"port"`
-	Timeout time.Duration `koanf:"timeout"`
+	Host string `koanf:"host"`
+	Port string `koanf:"port"`
+	// Endpoints of the cluster members, host:port being dialed if empty
+	Endpoints        []string      `koanf:"endpoints"`
+	Username         string        `koanf:"username"`
+	Password         string        `koanf:"password"`
+	Namespace        string        `koanf:"namespace"`
+	Timeout          time.Duration `koanf:"timeout"`
+	KeepAliveTime    time.Duration `koanf:"keepalivetime"`
+	KeepAliveTimeout time.Duration `koanf:"keepalivetimeout"`
+	AutoSyncInterval time.Duration `koanf:"autosyncinterval"`
+	TLS              TLSConfig     `koanf:"tls"`
 }
 
 // DatabaseConfig related to database
@@ -67,62 +128,133 @@ type DatabaseConfig struct {
 	}
 }
 
-// CacheConfig related to Redis
+// CacheConfig related to Redis, caching the resource states read while the
+// etcd snapshot of a replica is not ready, e.g. at startup
 type CacheConfig struct {
 	Redis struct {
+		Enabled      bool          `koanf:"enabled"`
+		TTL          time.Duration `koanf:"ttl"`
 		RedisOptions redis.Options `koanf:"redisoptions"`
 	}
 }
 
 // TritonServerConfig related to Triton server
 type TritonServerConfig struct {
-	Host       string `koanf:"host"`
-	GrpcURI    string `koanf:"grpcuri"`
-	ModelStore string `koanf:"modelstore"`
+	Host       string    `koanf:"host"`
+	GrpcURI    string    `koanf:"grpcuri"`
+	ModelStore string    `koanf:"modelstore"`
+	TLS        TLSConfig `koanf:"tls"`
 }
 
 // ConnectorBackendConfig related to connector-backend
 type ConnectorBackendConfig struct {
-	Host        string `koanf:"host"`
-	PublicPort  int    `koanf:"publicport"`
-	PrivatePort int    `koanf:"privateport"`
-	HTTPS       struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
-	}
+	Host        string    `koanf:"host"`
+	PublicPort  int       `koanf:"publicport"`
+	PrivatePort int       `koanf:"privateport"`
+	TLS         TLSConfig `koanf:"tls"`
 }
 
 // ModelBackendConfig related to model-backend
 type ModelBackendConfig struct {
-	Host        string `koanf:"host"`
-	PublicPort  int    `koanf:"publicport"`
-	PrivatePort int    `koanf:"privateport"`
-	HTTPS       struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
-	}
+	Host        string    `koanf:"host"`
+	PublicPort  int       `koanf:"publicport"`
+	PrivatePort int       `koanf:"privateport"`
+	TLS         TLSConfig `koanf:"tls"`
 }
 
 // PipelineBackendConfig related to pipeline-backend
 type PipelineBackendConfig struct {
-	Host        string `koanf:"host"`
-	PublicPort  int    `koanf:"publicport"`
-	PrivatePort int    `koanf:"privateport"`
-	HTTPS       struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
-	}
+	Host        string    `koanf:"host"`
+	PublicPort  int       `koanf:"publicport"`
+	PrivatePort int       `koanf:"privateport"`
+	TLS         TLSConfig `koanf:"tls"`
 }
 
 // MgmtBackendConfig related to mgmt-backend
 type MgmtBackendConfig struct {
-	Host        string `koanf:"host"`
-	PublicPort  int    `koanf:"publicport"`
-	PrivatePort int    `koanf:"privateport"`
-	HTTPS       struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
+	Host        string    `koanf:"host"`
+	PublicPort  int       `koanf:"publicport"`
+	PrivatePort int       `koanf:"privateport"`
+	TLS         TLSConfig `koanf:"tls"`
+}
+
+// BackendServiceConfig related to a backend service probed by the controller,
+// the address and TLS settings being those of the grpc-health, http and tcp
+// kinds, the other kinds probing through the clients of the backends
+type BackendServiceConfig struct {
+	Name    string        `koanf:"name"`
+	Kind    string        `koanf:"kind"`
+	Address string        `koanf:"address"`
+	Service string        `koanf:"service"`
+	Timeout time.Duration `koanf:"timeout"`
+	TLS     TLSConfig     `koanf:"tls"`
+}
+
+// TLSConfig related to TLS settings used to dial an upstream, the client
+// certificate being presented to the upstreams requiring mTLS
+type TLSConfig struct {
+	Enabled            bool   `koanf:"enabled"`
+	CACert             string `koanf:"cacert"`
+	ClientCert         string `koanf:"clientcert"`
+	ClientKey          string `koanf:"clientkey"`
+	ServerName         string `koanf:"servername"`
+	InsecureSkipVerify bool   `koanf:"insecureskipverify"`
+}
+
+// NotifierConfig related to the notifications of resource state transitions,
+// the webhook deliveries being queued up to QueueSize and posted by Workers
+// workers
+type NotifierConfig struct {
+	Webhooks  []WebhookConfig `koanf:"webhooks"`
+	Workers   int             `koanf:"workers"`
+	QueueSize int             `koanf:"queuesize"`
+	Publisher PublisherConfig `koanf:"publisher"`
+}
+
+// PublisherConfig related to the message bus state transitions are
+// published to, using the Redis options of the cache for Redis Streams. The
+// events are queued up to QueueSize and published in order
+type PublisherConfig struct {
+	Kind      string `koanf:"kind"`
+	Stream    string `koanf:"stream"`
+	MaxLen    int64  `koanf:"maxlen"`
+	QueueSize int    `koanf:"queuesize"`
+}
+
+// WebhookConfig related to an HTTP webhook notified of state transitions,
+// an empty filter matches every resource type or state
+type WebhookConfig struct {
+	Name          string        `koanf:"name"`
+	URL           string        `koanf:"url"`
+	Secret        string        `koanf:"secret"`
+	ResourceTypes []string      `koanf:"resourcetypes"`
+	States        []string      `koanf:"states"`
+	Timeout       time.Duration `koanf:"timeout"`
+	MaxRetries    int           `koanf:"maxretries"`
+	RetryBackoff  time.Duration `koanf:"retrybackoff"`
+}
+
+// AuthConfig related to the authentication and authorization of the callers
+// of the controller API, authenticating with a client certificate signed by
+// the client CA or a JWT signed by a key of the JWKS file
+type AuthConfig struct {
+	Enabled  bool   `koanf:"enabled"`
+	ClientCA string `koanf:"clientca"`
+	JWT      struct {
+		JWKSFile string `koanf:"jwksfile"`
+		Issuer   string `koanf:"issuer"`
+		Audience string `koanf:"audience"`
 	}
+	Rules []AuthRuleConfig `koanf:"rules"`
+}
+
+// AuthRuleConfig related to the principals allowed to call the methods
+// matching a pattern, e.g. /vdp.controller.v1alpha.ControllerPrivateService/*,
+// optionally only on resources of the given types
+type AuthRuleConfig struct {
+	Method        string   `koanf:"method"`
+	ResourceTypes []string `koanf:"resourcetypes"`
+	Principals    []string `koanf:"principals"`
 }
 
 // LogConfig related to logging
@@ -164,7 +296,122 @@ func Init() error {
 	return ValidateConfig(&Config)
 }
 
+// livenessServices are the backends the liveness probe kind has a client for
+var livenessServices = []string{
+	util.SERVICE_CONNECTOR_BACKEND,
+	util.SERVICE_MODEL_BACKEND,
+	util.SERVICE_PIPELINE_BACKEND,
+	util.SERVICE_MGMT_BACKEND,
+}
+
+// clientSection returns the configuration section of the client a backend
+// service is probed through, empty if there is none
+func clientSection(s BackendServiceConfig) string {
+	if s.Kind == ProbeKindTriton {
+		return "tritonserver"
+	}
+	switch s.Name {
+	case util.SERVICE_CONNECTOR_BACKEND:
+		return "connectorbackend"
+	case util.SERVICE_MODEL_BACKEND:
+		return "modelbackend"
+	case util.SERVICE_PIPELINE_BACKEND:
+		return "pipelinebackend"
+	case util.SERVICE_MGMT_BACKEND:
+		return "mgmtbackend"
+	default:
+		return ""
+	}
+}
+
 // ValidateConfig is for custom validation rules for the configuration
 func ValidateConfig(cfg *AppConfig) error {
+	names := make(map[string]bool)
+	for _, s := range cfg.BackendServices {
+		if s.Name == "" {
+			return fmt.Errorf("backend service name is required")
+		}
+		if names[s.Name] {
+			return fmt.Errorf("backend service %s is defined more than once", s.Name)
+		}
+		names[s.Name] = true
+
+		switch s.Kind {
+		case ProbeKindLiveness, ProbeKindTriton:
+			// these kinds probe through the clients of the backends, dialed
+			// from their own configuration
+			if s.Kind == ProbeKindLiveness && clientSection(s) == "" {
+				return fmt.Errorf("backend service %s with probe kind %s must be one of %s", s.Name, s.Kind, strings.Join(livenessServices, ", "))
+			}
+			if s.Address != "" || s.TLS.Enabled {
+				return fmt.Errorf("backend service %s with probe kind %s does not accept an address or TLS, its client is configured in the %s section", s.Name, s.Kind, clientSection(s))
+			}
+		case ProbeKindGRPCHealth, ProbeKindHTTP, ProbeKindTCP:
+			if s.Address == "" {
+				return fmt.Errorf("backend service %s with probe kind %s requires an address", s.Name, s.Kind)
+			}
+		default:
+			return fmt.Errorf("backend service %s has unknown probe kind %q", s.Name, s.Kind)
+		}
+	}
+
+	for _, name := range cfg.Server.Startup.RequiredBackends {
+		if !names[name] {
+			return fmt.Errorf("required backend %s is not a backend service", name)
+		}
+	}
+
+	if len(cfg.Etcd.Endpoints) == 0 && cfg.Etcd.Host == "" {
+		return fmt.Errorf("etcd endpoints or host is required")
+	}
+	if (cfg.Etcd.Username == "") != (cfg.Etcd.Password == "") {
+		return fmt.Errorf("etcd username and password must be set together")
+	}
+	if cfg.Etcd.Namespace != "" && !strings.HasSuffix(cfg.Etcd.Namespace, "/") {
+		return fmt.Errorf("etcd namespace %q must end with /", cfg.Etcd.Namespace)
+	}
+
+	webhooks := make(map[string]bool)
+	for _, w := range cfg.Notifier.Webhooks {
+		if w.Name == "" {
+			return fmt.Errorf("webhook name is required")
+		}
+		if webhooks[w.Name] {
+			return fmt.Errorf("webhook %s is defined more than once", w.Name)
+		}
+		webhooks[w.Name] = true
+
+		if w.URL == "" {
+			return fmt.Errorf("webhook %s requires a url", w.Name)
+		}
+	}
+
+	switch cfg.Notifier.Publisher.Kind {
+	case "", PublisherKindNoop:
+	case PublisherKindRedisStreams:
+		if cfg.Notifier.Publisher.Stream == "" {
+			return fmt.Errorf("publisher of kind %s requires a stream", cfg.Notifier.Publisher.Kind)
+		}
+	default:
+		return fmt.Errorf("unknown publisher kind %q", cfg.Notifier.Publisher.Kind)
+	}
+
+	if cfg.Auth.Enabled {
+		if cfg.Auth.ClientCA == "" && cfg.Auth.JWT.JWKSFile == "" {
+			return fmt.Errorf("auth requires a client CA or a JWKS file")
+		}
+		if cfg.Auth.ClientCA != "" && (cfg.Server.HTTPS.Cert == "" || cfg.Server.HTTPS.Key == "") {
+			return fmt.Errorf("auth with a client CA requires the server HTTPS certificate and key")
+		}
+		for _, r := range cfg.Auth.Rules {
+			if r.Method == "" {
+				return fmt.Errorf("auth rule method is required")
+			}
+			if len(r.Principals) == 0 {
+				return fmt.Errorf("auth rule of method %s requires principals", r.Method)
+			}
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/instill-ai/controller/config"
+)
+
+func TestValidateBackendServices(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		service config.BackendServiceConfig
+		valid   bool
+	}{
+		{
+			name:    "liveness of a known backend",
+			service: config.BackendServiceConfig{Name: "model-backend", Kind: config.ProbeKindLiveness},
+			valid:   true,
+		},
+		{
+			name:    "liveness of an unknown backend",
+			service: config.BackendServiceConfig{Name: "modle-backend", Kind: config.ProbeKindLiveness},
+		},
+		{
+			name:    "liveness with an address",
+			service: config.BackendServiceConfig{Name: "model-backend", Kind: config.ProbeKindLiveness, Address: "model-backend:3083"},
+		},
+		{
+			name:    "liveness with TLS",
+			service: config.BackendServiceConfig{Name: "model-backend", Kind: config.ProbeKindLiveness, TLS: config.TLSConfig{Enabled: true}},
+		},
+		{
+			name:    "triton with an address",
+			service: config.BackendServiceConfig{Name: "triton-server", Kind: config.ProbeKindTriton, Address: "triton-server:8001"},
+		},
+		{
+			name:    "grpc-health with an address",
+			service: config.BackendServiceConfig{Name: "redis-proxy", Kind: config.ProbeKindGRPCHealth, Address: "redis-proxy:50051"},
+			valid:   true,
+		},
+		{
+			name:    "grpc-health without an address",
+			service: config.BackendServiceConfig{Name: "redis-proxy", Kind: config.ProbeKindGRPCHealth},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.AppConfig{
+				Etcd:            config.EtcdConfig{Host: "etcd"},
+				BackendServices: []config.BackendServiceConfig{tc.service},
+			}
+
+			err := config.ValidateConfig(&cfg)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
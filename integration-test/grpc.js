@@ -38,6 +38,11 @@ export default function (data) {
         'Readiness Status is OK': (r) => r && r.status === grpc.StatusOK,
         'Response status is SERVING_STATUS_SERVING': (r) => r && r.message.healthCheckResponse.status === "SERVING_STATUS_SERVING",
       });
+
+      check(client.invoke('vdp.controller.v1alpha.ControllerPrivateService/GetSystemHealth', {}), {
+        'GetSystemHealth Status is OK': (r) => r && r.status === grpc.StatusOK,
+        'GetSystemHealth response has services': (r) => r && r.message.services.length > 0,
+      });
       client.close();
     });
 
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/instill-ai/controller/config"
+)
+
+// Principal is the authenticated identity of a caller
+type Principal struct {
+	// Name is the subject of the client certificate or of the JWT
+	Name string
+	// Mechanism is how the caller authenticated, mtls or jwt
+	Mechanism string
+}
+
+func (p Principal) String() string {
+	if p.Name == "" {
+		return "anonymous"
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, p.Mechanism)
+}
+
+// Authorizer grants the calls of principals to methods following the rules
+// of the allow-lists, the methods without rule being granted to every
+// authenticated principal
+type Authorizer struct {
+	rules []config.AuthRuleConfig
+}
+
+// NewAuthorizer returns an authorizer of the given rules
+func NewAuthorizer(rules []config.AuthRuleConfig) *Authorizer {
+	return &Authorizer{rules: rules}
+}
+
+// Authorize tells whether a principal may call a method, fullMethod being of
+// the form /package.Service/Method and matched by the rule methods as a path
+// pattern, and resourceType being the type of the resource the call is
+// about, if any
+func (a *Authorizer) Authorize(principal Principal, fullMethod string, resourceType string) error {
+	governed := false
+	for _, rule := range a.rules {
+		if ok, _ := path.Match(rule.Method, fullMethod); !ok {
+			continue
+		}
+		if len(rule.ResourceTypes) > 0 && !contains(rule.ResourceTypes, resourceType) {
+			continue
+		}
+
+		governed = true
+		if contains(rule.Principals, "*") || contains(rule.Principals, principal.Name) {
+			return nil
+		}
+	}
+
+	if governed {
+		if resourceType != "" {
+			return fmt.Errorf("%s is not allowed to call %s on %s", principal, fullMethod, resourceType)
+		}
+		return fmt.Errorf("%s is not allowed to call %s", principal, fullMethod)
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
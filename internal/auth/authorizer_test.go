@@ -0,0 +1,28 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/auth"
+)
+
+func TestAuthorize(t *testing.T) {
+	const updateResource = "/vdp.controller.v1alpha.ControllerPrivateService/UpdateResource"
+
+	authorizer := auth.NewAuthorizer([]config.AuthRuleConfig{
+		{Method: updateResource, ResourceTypes: []string{"models"}, Principals: []string{"model-backend"}},
+		{Method: "/vdp.controller.v1alpha.ControllerPrivateService/Delete*", Principals: []string{"*"}},
+	})
+
+	modelBackend := auth.Principal{Name: "model-backend", Mechanism: "jwt"}
+	pipelineBackend := auth.Principal{Name: "pipeline-backend", Mechanism: "mtls"}
+
+	assert.NoError(t, authorizer.Authorize(modelBackend, updateResource, "models"))
+	assert.Error(t, authorizer.Authorize(pipelineBackend, updateResource, "models"))
+	// no rule governs the updates of pipelines
+	assert.NoError(t, authorizer.Authorize(pipelineBackend, updateResource, "pipelines"))
+	assert.NoError(t, authorizer.Authorize(pipelineBackend, "/vdp.controller.v1alpha.ControllerPrivateService/DeleteResource", "models"))
+}
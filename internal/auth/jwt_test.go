@@ -0,0 +1,109 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/instill-ai/controller/internal/auth"
+)
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sign(t *testing.T, alg string, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := encode(header) + "." + encode(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		s, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+		signature = s
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signingInput + "." + encode(signature)
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q}
+	]}`,
+		encode(rsaKey.N.Bytes()), encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+		encode(ecKey.X.FillBytes(make([]byte, 32))), encode(ecKey.Y.FillBytes(make([]byte, 32))),
+	)
+	keySet, err := auth.ParseKeySet([]byte(jwks))
+	require.NoError(t, err)
+
+	now := time.Now()
+	valid := map[string]interface{}{
+		"sub": "model-backend",
+		"iss": "instill",
+		"aud": []string{"controller"},
+		"exp": now.Add(time.Hour).Unix(),
+	}
+
+	t.Run("RS256", func(t *testing.T) {
+		claims, err := keySet.Verify(sign(t, "RS256", "rsa", rsaKey, valid), "instill", "controller", now)
+		require.NoError(t, err)
+		assert.Equal(t, "model-backend", claims.Subject)
+	})
+	t.Run("ES256", func(t *testing.T) {
+		claims, err := keySet.Verify(sign(t, "ES256", "ec", ecKey, valid), "instill", "controller", now)
+		require.NoError(t, err)
+		assert.Equal(t, "model-backend", claims.Subject)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		token := sign(t, "RS256", "rsa", rsaKey, valid)
+		other := sign(t, "RS256", "rsa", rsaKey, map[string]interface{}{"sub": "admin", "exp": valid["exp"]})
+		forged := strings.Split(token, ".")
+		forged[1] = strings.Split(other, ".")[1]
+		_, err := keySet.Verify(strings.Join(forged, "."), "", "", now)
+		assert.Error(t, err)
+	})
+	t.Run("expired", func(t *testing.T) {
+		_, err := keySet.Verify(sign(t, "RS256", "rsa", rsaKey, valid), "instill", "controller", now.Add(2*time.Hour))
+		assert.Error(t, err)
+	})
+	t.Run("wrong audience", func(t *testing.T) {
+		_, err := keySet.Verify(sign(t, "RS256", "rsa", rsaKey, valid), "instill", "mgmt", now)
+		assert.Error(t, err)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := keySet.Verify(sign(t, "RS256", "other", rsaKey, valid), "", "", now)
+		assert.Error(t, err)
+	})
+	t.Run("algorithm none", func(t *testing.T) {
+		token := sign(t, "RS256", "rsa", rsaKey, valid)
+		header, _ := json.Marshal(map[string]string{"alg": "none", "kid": "rsa"})
+		_, err := keySet.Verify(encode(header)+"."+strings.Split(token, ".")[1]+".", "", "", now)
+		assert.Error(t, err)
+	})
+}
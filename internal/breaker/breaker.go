@@ -0,0 +1,167 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = iota
+	// StateOpen short-circuits every call until the cooldown has elapsed
+	StateOpen
+	// StateHalfOpen lets a single trial call through
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrOpen is returned, wrapped, when a call is short-circuited
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker is a circuit breaker guarding the calls to a backend
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// New returns a circuit breaker for the named backend, which trips after
+// threshold consecutive failures and allows a trial call after cooldown
+func New(name string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Name returns the name of the backend guarded by the breaker
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// IsOpen reports whether calls to the backend are currently short-circuited
+func (b *Breaker) IsOpen() bool {
+	return b.State() == StateOpen
+}
+
+// Allow returns an error wrapping ErrOpen if the call must be short-circuited
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return fmt.Errorf("%s is down: %w", b.name, ErrOpen)
+		}
+		b.state = StateHalfOpen
+		b.trial = true
+		return nil
+	case StateHalfOpen:
+		if b.trial {
+			return fmt.Errorf("%s is down: %w", b.name, ErrOpen)
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a successful call, closing the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.trial = false
+}
+
+// Failure records a failed call, opening the breaker once the threshold of
+// consecutive failures is reached or if the trial call failed
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Record records the outcome of a call made without Allow
+func (b *Breaker) Record(err error) {
+	if IsBackendFailure(err) {
+		b.Failure()
+	} else {
+		b.Success()
+	}
+}
+
+// Do calls fn unless the breaker is open, and records its outcome
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.Record(err)
+	return err
+}
+
+// IsBackendFailure reports whether err means the backend could not serve the
+// call, as opposed to the call being rejected by a healthy backend
+func IsBackendFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
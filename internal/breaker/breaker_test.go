@@ -0,0 +1,45 @@
+package breaker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/controller/internal/breaker"
+)
+
+func TestBreaker(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	t.Run("trips on consecutive failures", func(t *testing.T) {
+		b := breaker.New("model-backend", 2, time.Hour)
+
+		assert.ErrorIs(t, b.Do(func() error { return unavailable }), unavailable)
+		assert.False(t, b.IsOpen())
+		assert.ErrorIs(t, b.Do(func() error { return unavailable }), unavailable)
+		assert.True(t, b.IsOpen())
+
+		err := b.Do(func() error { return nil })
+		assert.True(t, errors.Is(err, breaker.ErrOpen))
+	})
+
+	t.Run("ignores rejected calls", func(t *testing.T) {
+		b := breaker.New("model-backend", 1, time.Hour)
+
+		assert.Error(t, b.Do(func() error { return status.Error(codes.NotFound, "not found") }))
+		assert.False(t, b.IsOpen())
+	})
+
+	t.Run("closes after a successful trial", func(t *testing.T) {
+		b := breaker.New("model-backend", 1, 0)
+
+		assert.Error(t, b.Do(func() error { return unavailable }))
+		assert.Equal(t, breaker.StateHalfOpen, b.State())
+		assert.NoError(t, b.Do(func() error { return nil }))
+		assert.Equal(t, breaker.StateClosed, b.State())
+	})
+}
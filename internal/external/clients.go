@@ -0,0 +1,192 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+// connectParams are the reconnection backoff of the backend connections, capped
+// lower than the gRPC default so that a restarted backend is picked up quickly
+var connectParams = grpc.ConnectParams{
+	Backoff: backoff.Config{
+		BaseDelay:  time.Second,
+		Multiplier: 1.6,
+		Jitter:     0.2,
+		MaxDelay:   30 * time.Second,
+	},
+	MinConnectTimeout: 5 * time.Second,
+}
+
+// ClientManager owns the connections to the backends. They are dialed without
+// blocking, gRPC connecting them on first use and reconnecting them with
+// backoff, so that a backend down at startup is reported as unavailable
+// instead of failing the controller
+type ClientManager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// dialOpts are added to the options of every connection
+	dialOpts []grpc.DialOption
+
+	mu sync.Mutex
+	// conns are the connections of each backend, e.g. to its public and
+	// private ports
+	conns map[string][]*grpc.ClientConn
+}
+
+// NewClientManager returns a ClientManager, reporting the connectivity of the
+// backends in the controller.backend.connected gauge. The dial options are
+// added to those of every connection, e.g. a dialer in tests
+func NewClientManager(ctx context.Context, opts ...grpc.DialOption) *ClientManager {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := &ClientManager{
+		ctx:      ctx,
+		cancel:   cancel,
+		dialOpts: opts,
+		conns:    map[string][]*grpc.ClientConn{},
+	}
+
+	if _, err := otel.Meter("controller.external.meter").Int64ObservableGauge(
+		"controller.backend.connected",
+		metric.WithDescription("Whether the connections to a backend are ready, by backend"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for backend, state := range m.States() {
+				connected := int64(0)
+				if state == connectivity.Ready {
+					connected = 1
+				}
+				o.Observe(connected, metric.WithAttributes(attribute.String("backend", backend)))
+			}
+			return nil
+		}),
+	); err != nil {
+		logger.Warn(fmt.Sprintf("[Clients] cannot create the connectivity gauge: %v", err))
+	}
+
+	return m
+}
+
+// Dial returns a connection to a backend, failing only if it cannot be set
+// up, e.g. on an invalid TLS configuration, and never because the backend is
+// unreachable
+func (m *ClientManager) Dial(ctx context.Context, backend string, target string, tlsConfig config.TLSConfig) (*grpc.ClientConn, error) {
+	clientDialOpts, err := NewClientDialOption(tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up the %s client: %w", backend, err)
+	}
+
+	opts := append([]grpc.DialOption{clientDialOpts, grpc.WithConnectParams(connectParams)}, m.dialOpts...)
+	clientConn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up the %s client: %w", backend, err)
+	}
+
+	m.mu.Lock()
+	m.conns[backend] = append(m.conns[backend], clientConn)
+	m.mu.Unlock()
+
+	go m.watch(backend, target, clientConn)
+
+	return clientConn, nil
+}
+
+// watch logs the connection losses and recoveries of a connection until it is
+// closed
+func (m *ClientManager) watch(backend string, target string, clientConn *grpc.ClientConn) {
+	logger, _ := logger.GetZapLogger(m.ctx)
+
+	failed := false
+	state := clientConn.GetState()
+	for clientConn.WaitForStateChange(m.ctx, state) {
+		state = clientConn.GetState()
+		switch {
+		case state == connectivity.TransientFailure && !failed:
+			failed = true
+			logger.Warn(fmt.Sprintf("[Clients] connection to %s at %s lost, reconnecting", backend, target))
+		case state == connectivity.Ready && failed:
+			failed = false
+			logger.Info(fmt.Sprintf("[Clients] connection to %s at %s recovered", backend, target))
+		case state == connectivity.Shutdown:
+			return
+		}
+	}
+}
+
+// States returns the connectivity state of every backend dialed
+func (m *ClientManager) States() map[string]connectivity.State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	states := make(map[string]connectivity.State, len(m.conns))
+	for backend := range m.conns {
+		states[backend] = m.state(backend)
+	}
+	return states
+}
+
+// state returns the connectivity state of a backend, i.e. the least ready of
+// its connections, Shutdown if it has none
+func (m *ClientManager) state(backend string) connectivity.State {
+	conns := m.conns[backend]
+	if len(conns) == 0 {
+		return connectivity.Shutdown
+	}
+
+	state := conns[0].GetState()
+	for _, clientConn := range conns[1:] {
+		if s := clientConn.GetState(); stateRank(s) < stateRank(state) {
+			state = s
+		}
+	}
+	return state
+}
+
+// stateRank orders the connectivity states from the least to the most ready,
+// idle connections being only connected on first use
+func stateRank(state connectivity.State) int {
+	switch state {
+	case connectivity.Shutdown:
+		return 0
+	case connectivity.TransientFailure:
+		return 1
+	case connectivity.Connecting:
+		return 2
+	case connectivity.Idle:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Close closes the connections to every backend
+func (m *ClientManager) Close() error {
+	m.cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var closeErr error
+	for _, conns := range m.conns {
+		for _, clientConn := range conns {
+			if err := clientConn.Close(); err != nil && closeErr == nil {
+				closeErr = err
+			}
+		}
+	}
+	m.conns = map[string][]*grpc.ClientConn{}
+
+	return closeErr
+}
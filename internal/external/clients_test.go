@@ -0,0 +1,62 @@
+package external_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/external"
+)
+
+func TestClientManager(t *testing.T) {
+	t.Run("unreachable backend", func(t *testing.T) {
+		m := external.NewClientManager(context.Background())
+		defer m.Close()
+
+		// the backend being down does not fail the setup of its client
+		_, err := m.Dial(context.Background(), "model-backend", "localhost:1", config.TLSConfig{})
+		require.NoError(t, err)
+
+		require.Contains(t, m.States(), "model-backend")
+		assert.NotEqual(t, connectivity.Ready, m.States()["model-backend"])
+	})
+
+	t.Run("invalid TLS configuration", func(t *testing.T) {
+		m := external.NewClientManager(context.Background())
+		defer m.Close()
+
+		_, err := m.Dial(context.Background(), "model-backend", "localhost:1", config.TLSConfig{Enabled: true, ClientCert: "client.crt"})
+		assert.Error(t, err)
+		assert.Empty(t, m.States())
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		m := external.NewClientManager(context.Background())
+
+		_, err := m.Dial(context.Background(), "model-backend", "localhost:1", config.TLSConfig{})
+		require.NoError(t, err)
+
+		require.NoError(t, m.Close())
+		assert.Empty(t, m.States())
+	})
+}
+
+func TestInitClients(t *testing.T) {
+	tlsConfig := config.Config.ModelBackend.TLS
+	t.Cleanup(func() {
+		config.Config.ModelBackend.TLS = tlsConfig
+	})
+
+	m := external.NewClientManager(context.Background())
+	defer m.Close()
+
+	config.Config.ModelBackend.TLS = config.TLSConfig{Enabled: true, ClientKey: "client.key"}
+
+	client, err := external.InitModelPublicServiceClient(context.Background(), m)
+	assert.Error(t, err)
+	assert.Nil(t, client)
+}
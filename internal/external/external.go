@@ -2,15 +2,13 @@ package external
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
-
 	"github.com/instill-ai/controller/config"
 	"github.com/instill-ai/controller/internal/triton"
+	"github.com/instill-ai/controller/internal/util"
 	"github.com/instill-ai/controller/pkg/logger"
 
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
@@ -18,31 +16,53 @@ import (
 	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
 	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
 	etcdv3 "go.etcd.io/etcd/client/v3"
+	etcdnamespace "go.etcd.io/etcd/client/v3/namespace"
 )
 
-// InitTritonServiceClient initialises a TritonServiceClient instance
+// InitEtcdServiceClient initialises an etcd client, whose keys are
+// transparently prefixed with the configured namespace
 func InitEtcdServiceClient(ctx context.Context) *etcdv3.Client {
+	client := InitEtcdRootServiceClient(ctx)
+
+	if namespace := config.Config.Etcd.Namespace; namespace != "" {
+		client.KV = etcdnamespace.NewKV(client.KV, namespace)
+		client.Watcher = etcdnamespace.NewWatcher(client.Watcher, namespace)
+		client.Lease = etcdnamespace.NewLease(client.Lease, namespace)
+	}
+
+	return client
+}
+
+// InitEtcdRootServiceClient initialises an etcd client ignoring the
+// configured namespace, to access the keys of every namespace
+func InitEtcdRootServiceClient(ctx context.Context) *etcdv3.Client {
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var clientDialOpts grpc.DialOption
-	if config.Config.ConnectorBackend.HTTPS.Cert != "" && config.Config.ConnectorBackend.HTTPS.Key != "" {
-		creds, err := credentials.NewServerTLSFromFile(config.Config.ConnectorBackend.HTTPS.Cert, config.Config.ConnectorBackend.HTTPS.Key)
-		if err != nil {
+	// the etcd client sets up its transport credentials from its TLS config
+	var tlsConfig *tls.Config
+	if config.Config.Etcd.TLS.Enabled {
+		var err error
+		if tlsConfig, err = NewClientTLSConfig(config.Config.Etcd.TLS); err != nil {
 			logger.Fatal(err.Error())
 		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
 	}
 
-	host := config.Config.Etcd.Host
-	port := config.Config.Etcd.Port
-	timeout := config.Config.Etcd.Timeout * time.Second
+	endpoints := config.Config.Etcd.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []string{fmt.Sprintf("%s:%s", config.Config.Etcd.Host, config.Config.Etcd.Port)}
+	}
+
 	// Create etcd client
 	client, err := etcdv3.New(etcdv3.Config{
-		Endpoints:   []string{fmt.Sprintf("%s:%s", host, port)}, //TODO: multiple nodes
-		DialTimeout: timeout,
-		DialOptions: []grpc.DialOption{clientDialOpts},
+		Endpoints:            endpoints,
+		Username:             config.Config.Etcd.Username,
+		Password:             config.Config.Etcd.Password,
+		DialTimeout:          config.Config.Etcd.Timeout * time.Second,
+		DialKeepAliveTime:    config.Config.Etcd.KeepAliveTime * time.Second,
+		DialKeepAliveTimeout: config.Config.Etcd.KeepAliveTimeout * time.Second,
+		// the member list is synced to follow the cluster membership changes
+		AutoSyncInterval: config.Config.Etcd.AutoSyncInterval * time.Second,
+		TLS:              tlsConfig,
 	})
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -51,189 +71,82 @@ func InitEtcdServiceClient(ctx context.Context) *etcdv3.Client {
 	return client
 }
 
-// InitTritonServiceClient initialises a TritonServiceClient instance
-func InitTritonServiceClient(ctx context.Context) (inferenceserver.GRPCInferenceServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-	grpcUri := config.Config.TritonServer.GrpcURI
-	// Connect to triton grpc server
-	clientConn, err := grpc.Dial(grpcUri, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// InitTritonServiceClient initialises a TritonServiceClient instance, failing only on an invalid configuration
+func InitTritonServiceClient(ctx context.Context, m *ClientManager) (inferenceserver.GRPCInferenceServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_TRITON_SERVER, config.Config.TritonServer.GrpcURI, config.Config.TritonServer.TLS)
 	if err != nil {
-		logger.Fatal(err.Error())
+		return nil, err
 	}
 
-	return inferenceserver.NewGRPCInferenceServiceClient(clientConn), clientConn
+	return inferenceserver.NewGRPCInferenceServiceClient(clientConn), nil
 }
 
-// InitConnectorPublicServiceClient initialises a ConnectorPublicServiceClient instance
-func InitConnectorPublicServiceClient(ctx context.Context) (connectorPB.ConnectorPublicServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	if config.Config.ConnectorBackend.HTTPS.Cert != "" && config.Config.ConnectorBackend.HTTPS.Key != "" {
-		creds, err := credentials.NewServerTLSFromFile(config.Config.ConnectorBackend.HTTPS.Cert, config.Config.ConnectorBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.ConnectorBackend.Host, config.Config.ConnectorBackend.PublicPort), clientDialOpts)
+// InitConnectorPublicServiceClient initialises a ConnectorPublicServiceClient instance, failing only on an invalid configuration
+func InitConnectorPublicServiceClient(ctx context.Context, m *ClientManager) (connectorPB.ConnectorPublicServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_CONNECTOR_BACKEND, fmt.Sprintf("%v:%v", config.Config.ConnectorBackend.Host, config.Config.ConnectorBackend.PublicPort), config.Config.ConnectorBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return connectorPB.NewConnectorPublicServiceClient(clientConn), clientConn
+	return connectorPB.NewConnectorPublicServiceClient(clientConn), nil
 }
 
-// InitConnectorPrivateServiceClient initialises a ConnectorPrivateServiceClient instance
-func InitConnectorPrivateServiceClient(ctx context.Context) (connectorPB.ConnectorPrivateServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	if config.Config.ConnectorBackend.HTTPS.Cert != "" && config.Config.ConnectorBackend.HTTPS.Key != "" {
-		creds, err := credentials.NewServerTLSFromFile(config.Config.ConnectorBackend.HTTPS.Cert, config.Config.ConnectorBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.ConnectorBackend.Host, config.Config.ConnectorBackend.PrivatePort), clientDialOpts)
+// InitConnectorPrivateServiceClient initialises a ConnectorPrivateServiceClient instance, failing only on an invalid configuration
+func InitConnectorPrivateServiceClient(ctx context.Context, m *ClientManager) (connectorPB.ConnectorPrivateServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_CONNECTOR_BACKEND, fmt.Sprintf("%v:%v", config.Config.ConnectorBackend.Host, config.Config.ConnectorBackend.PrivatePort), config.Config.ConnectorBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return connectorPB.NewConnectorPrivateServiceClient(clientConn), clientConn
+	return connectorPB.NewConnectorPrivateServiceClient(clientConn), nil
 }
 
-// InitModelPublicServiceClient initialises a ModelPublicServiceClient instance
-func InitModelPublicServiceClient(ctx context.Context) (modelPB.ModelPublicServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	if config.Config.ModelBackend.HTTPS.Cert != "" && config.Config.ModelBackend.HTTPS.Key != "" {
-		creds, err := credentials.NewServerTLSFromFile(config.Config.ModelBackend.HTTPS.Cert, config.Config.ModelBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.ModelBackend.Host, config.Config.ModelBackend.PublicPort), clientDialOpts)
+// InitModelPublicServiceClient initialises a ModelPublicServiceClient instance, failing only on an invalid configuration
+func InitModelPublicServiceClient(ctx context.Context, m *ClientManager) (modelPB.ModelPublicServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_MODEL_BACKEND, fmt.Sprintf("%v:%v", config.Config.ModelBackend.Host, config.Config.ModelBackend.PublicPort), config.Config.ModelBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return modelPB.NewModelPublicServiceClient(clientConn), clientConn
+	return modelPB.NewModelPublicServiceClient(clientConn), nil
 }
 
-// InitModelPrivateServiceClient initialises a ModelPrivateServiceClient instance
-func InitModelPrivateServiceClient(ctx context.Context) (modelPB.ModelPrivateServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	if config.Config.ModelBackend.HTTPS.Cert != "" && config.Config.ModelBackend.HTTPS.Key != "" {
-		creds, err := credentials.NewServerTLSFromFile(config.Config.ModelBackend.HTTPS.Cert, config.Config.ModelBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.ModelBackend.Host, config.Config.ModelBackend.PrivatePort), clientDialOpts)
+// InitModelPrivateServiceClient initialises a ModelPrivateServiceClient instance, failing only on an invalid configuration
+func InitModelPrivateServiceClient(ctx context.Context, m *ClientManager) (modelPB.ModelPrivateServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_MODEL_BACKEND, fmt.Sprintf("%v:%v", config.Config.ModelBackend.Host, config.Config.ModelBackend.PrivatePort), config.Config.ModelBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return modelPB.NewModelPrivateServiceClient(clientConn), clientConn
+	return modelPB.NewModelPrivateServiceClient(clientConn), nil
 }
 
-// InitMgmtPublicServiceClient initialises a MgmtPublicServiceClient instance
-func InitMgmtPublicServiceClient(ctx context.Context) (mgmtPB.MgmtPublicServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	var creds credentials.TransportCredentials
-	var err error
-	if config.Config.MgmtBackend.HTTPS.Cert != "" && config.Config.MgmtBackend.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.MgmtBackend.HTTPS.Cert, config.Config.MgmtBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.MgmtBackend.Host, config.Config.MgmtBackend.PublicPort), clientDialOpts)
+// InitMgmtPublicServiceClient initialises a MgmtPublicServiceClient instance, failing only on an invalid configuration
+func InitMgmtPublicServiceClient(ctx context.Context, m *ClientManager) (mgmtPB.MgmtPublicServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_MGMT_BACKEND, fmt.Sprintf("%v:%v", config.Config.MgmtBackend.Host, config.Config.MgmtBackend.PublicPort), config.Config.MgmtBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return mgmtPB.NewMgmtPublicServiceClient(clientConn), clientConn
+	return mgmtPB.NewMgmtPublicServiceClient(clientConn), nil
 }
 
-// InitPipelinePublicServiceClient initialises a PipelinePublicServiceClient instance
-func InitPipelinePublicServiceClient(ctx context.Context) (pipelinePB.PipelinePublicServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	var creds credentials.TransportCredentials
-	var err error
-	if config.Config.PipelineBackend.HTTPS.Cert != "" && config.Config.PipelineBackend.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.PipelineBackend.HTTPS.Cert, config.Config.PipelineBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.PipelineBackend.Host, config.Config.PipelineBackend.PublicPort), clientDialOpts)
+// InitPipelinePublicServiceClient initialises a PipelinePublicServiceClient instance, failing only on an invalid configuration
+func InitPipelinePublicServiceClient(ctx context.Context, m *ClientManager) (pipelinePB.PipelinePublicServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_PIPELINE_BACKEND, fmt.Sprintf("%v:%v", config.Config.PipelineBackend.Host, config.Config.PipelineBackend.PublicPort), config.Config.PipelineBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return pipelinePB.NewPipelinePublicServiceClient(clientConn), clientConn
+	return pipelinePB.NewPipelinePublicServiceClient(clientConn), nil
 }
 
-// InitPipelinePrivateServiceClient initialises a PipelinePrivateServiceClient instance
-func InitPipelinePrivateServiceClient(ctx context.Context) (pipelinePB.PipelinePrivateServiceClient, *grpc.ClientConn) {
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var clientDialOpts grpc.DialOption
-	var creds credentials.TransportCredentials
-	var err error
-	if config.Config.PipelineBackend.HTTPS.Cert != "" && config.Config.PipelineBackend.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.PipelineBackend.HTTPS.Cert, config.Config.PipelineBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
-	}
-
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.PipelineBackend.Host, config.Config.PipelineBackend.PrivatePort), clientDialOpts)
+// InitPipelinePrivateServiceClient initialises a PipelinePrivateServiceClient instance, failing only on an invalid configuration
+func InitPipelinePrivateServiceClient(ctx context.Context, m *ClientManager) (pipelinePB.PipelinePrivateServiceClient, error) {
+	clientConn, err := m.Dial(ctx, util.SERVICE_PIPELINE_BACKEND, fmt.Sprintf("%v:%v", config.Config.PipelineBackend.Host, config.Config.PipelineBackend.PrivatePort), config.Config.PipelineBackend.TLS)
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, nil
+		return nil, err
 	}
 
-	return pipelinePB.NewPipelinePrivateServiceClient(clientConn), clientConn
+	return pipelinePB.NewPipelinePrivateServiceClient(clientConn), nil
 }
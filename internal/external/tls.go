@@ -0,0 +1,212 @@
+package external
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/instill-ai/controller/config"
+)
+
+// reloadCheckInterval is how often the certificate files are checked for
+// changes, at most once per handshake
+const reloadCheckInterval = 10 * time.Second
+
+// reloadable is a value loaded from files and loaded again when any of them
+// changes on disk, e.g. a certificate renewed by cert-manager, the files being
+// checked at most once per interval
+type reloadable[T any] struct {
+	mu       sync.Mutex
+	paths    []string
+	load     func() (T, error)
+	interval time.Duration
+	value    T
+	modTimes []time.Time
+	checked  time.Time
+}
+
+func newReloadable[T any](load func() (T, error), interval time.Duration, paths ...string) (*reloadable[T], error) {
+	r := &reloadable[T]{paths: paths, load: load, interval: interval}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	r.value = value
+	r.modTimes = r.stat()
+	r.checked = time.Now()
+
+	return r, nil
+}
+
+// get returns the loaded value, loading it again if the files changed, and
+// keeping the previous one if they cannot be loaded, e.g. while a renewed
+// certificate is only partly written
+func (r *reloadable[T]) get() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < r.interval {
+		return r.value
+	}
+	r.checked = time.Now()
+
+	modTimes := r.stat()
+	changed := false
+	for i := range modTimes {
+		if !modTimes[i].Equal(r.modTimes[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return r.value
+	}
+
+	if value, err := r.load(); err == nil {
+		r.value = value
+		r.modTimes = modTimes
+	}
+
+	return r.value
+}
+
+func (r *reloadable[T]) stat() []time.Time {
+	modTimes := make([]time.Time, len(r.paths))
+	for i, path := range r.paths {
+		if info, err := os.Stat(path); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func loadCertificate(certFile string, keyFile string, interval time.Duration) (*reloadable[*tls.Certificate], error) {
+	return newReloadable(func() (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &cert, nil
+	}, interval, certFile, keyFile)
+}
+
+func loadCertPool(caFile string, interval time.Duration) (*reloadable[*x509.CertPool], error) {
+	return newReloadable(func() (*x509.CertPool, error) {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", caFile)
+		}
+		return pool, nil
+	}, interval, caFile)
+}
+
+// NewClientTLSConfig builds a client side tls.Config from a TLSConfig, the CA
+// bundle and client certificate being reloaded when they change on disk
+func NewClientTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, errors.New("client certificate and key must be set together")
+		}
+		cert, err := loadCertificate(cfg.ClientCert, cfg.ClientKey, reloadCheckInterval)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return cert.get(), nil
+		}
+	}
+
+	if cfg.CACert != "" && !cfg.InsecureSkipVerify {
+		roots, err := loadCertPool(cfg.CACert, reloadCheckInterval)
+		if err != nil {
+			return nil, err
+		}
+		// the default verification only knows static roots, the chain is
+		// verified against the current bundle instead
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			opts := x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         roots.get(),
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewServerTLSConfig builds a server side tls.Config serving HTTP/2 and
+// HTTP/1.1, verifying the client certificates given against the client CA
+// bundle if any, the files being reloaded when they change on disk
+func NewServerTLSConfig(certFile string, keyFile string, clientCAFile string) (*tls.Config, error) {
+	cert, err := loadCertificate(certFile, keyFile, reloadCheckInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientCAs *reloadable[*x509.CertPool]
+	if clientCAFile != "" {
+		if clientCAs, err = loadCertPool(clientCAFile, reloadCheckInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	newConfig := func() *tls.Config {
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{*cert.get()},
+			NextProtos:   []string{"h2", "http/1.1"},
+			MinVersion:   tls.VersionTLS12,
+		}
+		if clientCAs != nil {
+			tlsConfig.ClientCAs = clientCAs.get()
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		return tlsConfig
+	}
+
+	tlsConfig := newConfig()
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return newConfig(), nil
+	}
+
+	return tlsConfig, nil
+}
+
+// NewClientDialOption returns the transport credentials to dial an upstream
+// with, insecure unless TLS is enabled
+func NewClientDialOption(cfg config.TLSConfig) (grpc.DialOption, error) {
+	if !cfg.Enabled {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+
+	tlsConfig, err := NewClientTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
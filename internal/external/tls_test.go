@@ -0,0 +1,102 @@
+package external
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed CA certificate of the given common
+// name and its key to the given files, returning the certificate PEM
+func writeCertificate(t *testing.T, commonName string, certFile string, keyFile string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certPEM
+}
+
+func TestReloadable(t *testing.T) {
+	const interval = 50 * time.Millisecond
+
+	t.Run("certificate rewritten", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+		writeCertificate(t, "original", certFile, keyFile)
+
+		cert, err := loadCertificate(certFile, keyFile, interval)
+		require.NoError(t, err)
+
+		commonName := func() string {
+			leaf, err := x509.ParseCertificate(cert.get().Certificate[0])
+			require.NoError(t, err)
+			return leaf.Subject.CommonName
+		}
+		require.Equal(t, "original", commonName())
+
+		// a renewed certificate is served once the files are checked again
+		writeCertificate(t, "renewed", certFile, keyFile)
+		assert.Equal(t, "original", commonName())
+		assert.Eventually(t, func() bool { return commonName() == "renewed" }, 5*time.Second, interval/5)
+	})
+
+	t.Run("CA bundle rewritten", func(t *testing.T) {
+		dir := t.TempDir()
+		caFile := filepath.Join(dir, "ca.crt")
+		writeCertificate(t, "original", caFile, filepath.Join(dir, "ca.key"))
+
+		roots, err := loadCertPool(caFile, interval)
+		require.NoError(t, err)
+
+		renewedPEM := writeCertificate(t, "renewed", caFile, filepath.Join(dir, "ca.key"))
+		renewed := x509.NewCertPool()
+		require.True(t, renewed.AppendCertsFromPEM(renewedPEM))
+
+		assert.False(t, roots.get().Equal(renewed))
+		assert.Eventually(t, func() bool { return roots.get().Equal(renewed) }, 5*time.Second, interval/5)
+	})
+
+	t.Run("partly written file ignored", func(t *testing.T) {
+		dir := t.TempDir()
+		caFile := filepath.Join(dir, "ca.crt")
+		originalPEM := writeCertificate(t, "original", caFile, filepath.Join(dir, "ca.key"))
+		original := x509.NewCertPool()
+		require.True(t, original.AppendCertsFromPEM(originalPEM))
+
+		roots, err := loadCertPool(caFile, interval)
+		require.NoError(t, err)
+
+		// the previous bundle is kept until the file can be loaded
+		require.NoError(t, os.WriteFile(caFile, originalPEM[:len(originalPEM)/2], 0o600))
+		time.Sleep(2 * interval)
+		assert.True(t, roots.get().Equal(original))
+	})
+}
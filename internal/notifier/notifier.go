@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a transition of the stored state of a resource
+type Event struct {
+	ResourcePermalink string    `json:"resource_permalink"`
+	ResourceType      string    `json:"resource_type"`
+	PreviousState     string    `json:"previous_state,omitempty"`
+	State             string    `json:"state"`
+	Time              time.Time `json:"time"`
+}
+
+// Notifier notifies the transitions of resource states
+type Notifier interface {
+	// Enabled reports whether any transition can be notified, so that the
+	// previous states are only fetched when needed
+	Enabled() bool
+	// Notify notifies a transition without waiting for its delivery
+	Notify(ctx context.Context, event Event)
+	// Close stops notifying and waits for the pending deliveries until ctx
+	// is done
+	Close(ctx context.Context) error
+}
+
+type noopNotifier struct{}
+
+// NewNoopNotifier returns a notifier dropping every event
+func NewNoopNotifier() Notifier {
+	return noopNotifier{}
+}
+
+func (noopNotifier) Enabled() bool {
+	return false
+}
+
+func (noopNotifier) Notify(ctx context.Context, event Event) {}
+
+func (noopNotifier) Close(ctx context.Context) error {
+	return nil
+}
+
+type multiNotifier []Notifier
+
+// NewMultiNotifier returns a notifier forwarding every event to the enabled
+// ones among the given notifiers
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	enabled := multiNotifier{}
+	for _, n := range notifiers {
+		if n.Enabled() {
+			enabled = append(enabled, n)
+		}
+	}
+	return enabled
+}
+
+func (m multiNotifier) Enabled() bool {
+	return len(m) > 0
+}
+
+func (m multiNotifier) Notify(ctx context.Context, event Event) {
+	for _, n := range m {
+		n.Notify(ctx, event)
+	}
+}
+
+func (m multiNotifier) Close(ctx context.Context) error {
+	var closeErr error
+	for _, n := range m {
+		if err := n.Close(ctx); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
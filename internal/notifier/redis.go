@@ -0,0 +1,158 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+const (
+	defaultPublisherTimeout   = 5 * time.Second
+	defaultPublisherQueueSize = 1000
+)
+
+type redisStreamPublisher struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+
+	// ctx cancels the publication still running once the drain deadline of
+	// Close is over
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan Event
+	dropped atomic.Int64
+	done    chan struct{}
+}
+
+// NewPublisher returns the event publisher of the given configuration, the
+// no-op one unless a message bus is configured
+func NewPublisher(cfg config.PublisherConfig, redisClient *redis.Client) Notifier {
+	switch cfg.Kind {
+	case config.PublisherKindRedisStreams:
+		return NewRedisStreamPublisher(redisClient, cfg)
+	default:
+		return NewNoopNotifier()
+	}
+}
+
+// NewRedisStreamPublisher returns a publisher appending every event to a
+// Redis stream, trimmed to about MaxLen entries if positive, for consumers
+// to read with XREAD or consumer groups. The events are queued, being dropped
+// when the queue is full, and published one at a time so that the stream
+// keeps the order of the transitions
+func NewRedisStreamPublisher(client *redis.Client, cfg config.PublisherConfig) Notifier {
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultPublisherQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &redisStreamPublisher{
+		client: client,
+		stream: cfg.Stream,
+		maxLen: cfg.MaxLen,
+		ctx:    ctx,
+		cancel: cancel,
+		queue:  make(chan Event, queueSize),
+		done:   make(chan struct{}),
+	}
+
+	go p.work()
+
+	return p
+}
+
+func (p *redisStreamPublisher) Enabled() bool {
+	return true
+}
+
+// Notify queues an event without blocking the request updating the state,
+// dropping it if the queue is full or the publisher closed
+func (p *redisStreamPublisher) Notify(ctx context.Context, event Event) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		logger.Error(fmt.Sprintf("[Notifier] dropped event %s for stream %s, publisher closed (%d dropped)", event.ResourcePermalink, p.stream, p.dropped.Add(1)))
+		return
+	}
+
+	select {
+	case p.queue <- event:
+	default:
+		logger.Error(fmt.Sprintf("[Notifier] dropped event %s for stream %s, queue full (%d dropped)", event.ResourcePermalink, p.stream, p.dropped.Add(1)))
+	}
+}
+
+// Dropped returns the number of events dropped since the publisher started
+func (p *redisStreamPublisher) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// work publishes the queued events in order until the queue is closed and
+// drained
+func (p *redisStreamPublisher) work() {
+	defer close(p.done)
+
+	for event := range p.queue {
+		p.publish(event)
+	}
+}
+
+// publish appends an event to the stream, logging the failures rather than
+// failing the state update notifying it
+func (p *redisStreamPublisher) publish(event Event) {
+	ctx, cancel := context.WithTimeout(p.ctx, defaultPublisherTimeout)
+	defer cancel()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if err := p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]interface{}{
+			"resource_permalink": event.ResourcePermalink,
+			"resource_type":      event.ResourceType,
+			"previous_state":     event.PreviousState,
+			"state":              event.State,
+			"time":               event.Time.Format(time.RFC3339Nano),
+		},
+	}).Err(); err != nil {
+		logger.Error(fmt.Sprintf("[Notifier] cannot publish %s to stream %s: %v", event.ResourcePermalink, p.stream, err))
+	}
+}
+
+// Close stops queuing events and waits for the queued ones to be published,
+// cancelling the publications still running once ctx is done
+func (p *redisStreamPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-p.done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,191 @@
+package notifier_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/notifier"
+)
+
+func TestRedisStreamPublisher(t *testing.T) {
+	const stream = "controller:resource-states"
+
+	t.Run("published", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer client.Close()
+
+		p := notifier.NewRedisStreamPublisher(client, config.PublisherConfig{Stream: stream, MaxLen: 10})
+
+		p.Notify(context.Background(), notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			PreviousState:     "STATE_ACTIVE",
+			State:             "STATE_ERROR",
+			Time:              time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, p.Close(context.Background()))
+
+		messages, err := client.XRange(context.Background(), stream, "-", "+").Result()
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, map[string]interface{}{
+			"resource_permalink": "resources/uid/types/pipelines",
+			"resource_type":      "pipelines",
+			"previous_state":     "STATE_ACTIVE",
+			"state":              "STATE_ERROR",
+			"time":               "2023-05-01T12:00:00Z",
+		}, messages[0].Values)
+	})
+
+	t.Run("trimmed", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer client.Close()
+
+		p := notifier.NewRedisStreamPublisher(client, config.PublisherConfig{Stream: stream, MaxLen: 2})
+
+		for _, state := range []string{"STATE_INACTIVE", "STATE_ACTIVE", "STATE_ERROR"} {
+			p.Notify(context.Background(), notifier.Event{
+				ResourcePermalink: "resources/uid/types/pipelines",
+				ResourceType:      "pipelines",
+				State:             state,
+			})
+		}
+		require.NoError(t, p.Close(context.Background()))
+
+		length, err := client.XLen(context.Background(), stream).Result()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, length, int64(2))
+	})
+
+	t.Run("published in order", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer client.Close()
+
+		p := notifier.NewRedisStreamPublisher(client, config.PublisherConfig{Stream: stream})
+
+		// the transitions of a resource are read in the order they happened
+		states := make([]string, 100)
+		for i := range states {
+			states[i] = fmt.Sprintf("STATE_%d", i)
+			p.Notify(context.Background(), notifier.Event{
+				ResourcePermalink: "resources/uid/types/pipelines",
+				ResourceType:      "pipelines",
+				State:             states[i],
+			})
+		}
+		require.NoError(t, p.Close(context.Background()))
+
+		messages, err := client.XRange(context.Background(), stream, "-", "+").Result()
+		require.NoError(t, err)
+		published := make([]string, len(messages))
+		for i, message := range messages {
+			published[i], _ = message.Values["state"].(string)
+		}
+		assert.Equal(t, states, published)
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		received := make(chan struct{}, 1)
+		release := make(chan struct{})
+		addr := blockingServer(t, received, release)
+
+		client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+		defer client.Close()
+
+		p := notifier.NewRedisStreamPublisher(client, config.PublisherConfig{Stream: stream, QueueSize: 1})
+		dropped := p.(interface{ Dropped() int64 })
+
+		event := notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			State:             "STATE_ACTIVE",
+		}
+
+		// the worker blocks on the first publication, the second one is
+		// queued and the third one dropped
+		p.Notify(context.Background(), event)
+		<-received
+		p.Notify(context.Background(), event)
+		p.Notify(context.Background(), event)
+		assert.Equal(t, int64(1), dropped.Dropped())
+
+		close(release)
+		require.NoError(t, p.Close(context.Background()))
+
+		// no event is queued once closed
+		p.Notify(context.Background(), event)
+		assert.Equal(t, int64(2), dropped.Dropped())
+	})
+
+	t.Run("redis unavailable", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+		defer client.Close()
+
+		mr.Close()
+
+		p := notifier.NewRedisStreamPublisher(client, config.PublisherConfig{Stream: stream, MaxLen: 10})
+
+		// the error is logged, the state update notifying it is not failed
+		p.Notify(context.Background(), notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			State:             "STATE_ERROR",
+		})
+		require.NoError(t, p.Close(context.Background()))
+
+		require.NoError(t, mr.Restart())
+		length, err := client.XLen(context.Background(), stream).Result()
+		require.NoError(t, err)
+		assert.Zero(t, length)
+	})
+}
+
+// blockingServer accepts the connections of a Redis client without answering,
+// signalling the first request received, and closes them once released
+func blockingServer(t *testing.T, received chan<- struct{}, release <-chan struct{}) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := conn.Read(make([]byte, 1024)); err != nil {
+					return
+				}
+				select {
+				case received <- struct{}{}:
+				default:
+				}
+				<-release
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestNewPublisher(t *testing.T) {
+	assert.False(t, notifier.NewPublisher(config.PublisherConfig{Kind: config.PublisherKindNoop}, nil).Enabled())
+	assert.True(t, notifier.NewPublisher(config.PublisherConfig{Kind: config.PublisherKindRedisStreams, Stream: "controller:resource-states"}, redis.NewClient(&redis.Options{})).Enabled())
+}
@@ -0,0 +1,259 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+const (
+	// SignatureHeader carries the hex-encoded HMAC-SHA256 of the timestamp,
+	// a dot and the body, keyed with the webhook secret
+	SignatureHeader = "X-Controller-Signature"
+	// TimestampHeader carries the unix time the delivery was signed at
+	TimestampHeader = "X-Controller-Timestamp"
+
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookMaxRetries   = 3
+	defaultWebhookRetryBackoff = time.Second
+	defaultWebhookWorkers      = 4
+	defaultWebhookQueueSize    = 1000
+)
+
+// delivery is an event queued for a webhook
+type delivery struct {
+	webhook config.WebhookConfig
+	event   Event
+}
+
+type webhookNotifier struct {
+	webhooks []config.WebhookConfig
+	client   *http.Client
+
+	// ctx cancels the deliveries still running once the drain deadline of
+	// Close is over
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan delivery
+	wg     sync.WaitGroup
+}
+
+// NewWebhookNotifier returns a notifier posting the events matching the
+// filters of each webhook as JSON. The deliveries are queued, events being
+// dropped when the queue is full, and posted by a fixed number of workers
+func NewWebhookNotifier(cfg config.NotifierConfig) Notifier {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWebhookWorkers
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultWebhookQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &webhookNotifier{
+		webhooks: cfg.Webhooks,
+		client:   &http.Client{},
+		ctx:      ctx,
+		cancel:   cancel,
+		queue:    make(chan delivery, queueSize),
+	}
+
+	if n.Enabled() {
+		for i := 0; i < workers; i++ {
+			n.wg.Add(1)
+			go n.work()
+		}
+	}
+
+	return n
+}
+
+func (n *webhookNotifier) Enabled() bool {
+	return len(n.webhooks) > 0
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event Event) {
+	for _, webhook := range n.webhooks {
+		if !matches(webhook.ResourceTypes, event.ResourceType) || !matches(webhook.States, event.State) {
+			continue
+		}
+		n.enqueue(ctx, delivery{webhook: webhook, event: event})
+	}
+}
+
+// enqueue queues a delivery without blocking the request updating the state,
+// dropping it if the queue is full or the notifier closed
+func (n *webhookNotifier) enqueue(ctx context.Context, d delivery) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.closed {
+		logger.Error(fmt.Sprintf("[Notifier] dead letter for webhook %s, notifier closed: event %s", d.webhook.Name, d.event.ResourcePermalink))
+		return
+	}
+
+	select {
+	case n.queue <- d:
+	default:
+		logger.Error(fmt.Sprintf("[Notifier] dead letter for webhook %s, queue full: event %s", d.webhook.Name, d.event.ResourcePermalink))
+	}
+}
+
+// work delivers the queued events until the queue is closed and drained
+func (n *webhookNotifier) work() {
+	defer n.wg.Done()
+
+	for d := range n.queue {
+		n.deliver(n.ctx, d.webhook, d.event)
+	}
+}
+
+// Close stops queuing events and waits for the queued ones to be delivered,
+// cancelling the deliveries still running once ctx is done
+func (n *webhookNotifier) Close(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		n.cancel()
+		return nil
+	case <-ctx.Done():
+		n.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// deliver posts an event to a webhook, retrying with an exponential backoff
+// on network errors, 429 and 5xx responses, and logs it as a dead letter
+// once it gives up
+func (n *webhookNotifier) deliver(ctx context.Context, webhook config.WebhookConfig, event Event) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		logger.Error(fmt.Sprintf("[Notifier] webhook %s cannot encode %s: %v", webhook.Name, event.ResourcePermalink, err))
+		return
+	}
+
+	maxRetries := webhook.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultWebhookMaxRetries
+	}
+	backoff := webhook.RetryBackoff * time.Second
+	if backoff <= 0 {
+		backoff = defaultWebhookRetryBackoff
+	}
+
+	attempt := 0
+	for {
+		attempt++
+
+		retryable, err := n.post(ctx, webhook, body)
+		if err == nil {
+			return
+		}
+
+		// the deliveries cancelled on shutdown are not retried
+		if !retryable || attempt > maxRetries || ctx.Err() != nil {
+			logger.Error(fmt.Sprintf("[Notifier] dead letter for webhook %s after %d attempts: %v, event %s", webhook.Name, attempt, err, body))
+			return
+		}
+
+		logger.Warn(fmt.Sprintf("[Notifier] webhook %s attempt %d failed, retrying in %v: %v", webhook.Name, attempt, backoff, err))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			logger.Error(fmt.Sprintf("[Notifier] dead letter for webhook %s after %d attempts: %v, event %s", webhook.Name, attempt, ctx.Err(), body))
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a signed delivery, returning whether a failure is worth retrying
+func (n *webhookNotifier) post(ctx context.Context, webhook config.WebhookConfig, body []byte) (bool, error) {
+	timeout := webhook.Timeout * time.Second
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+}
+
+// Sign returns the signature of a delivery, for receivers to verify it
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func matches(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if f == value {
+			return true
+		}
+	}
+	return false
+}
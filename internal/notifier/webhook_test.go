@@ -0,0 +1,183 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/notifier"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("signed delivery after a retry", func(t *testing.T) {
+		var attempts atomic.Int32
+		received := make(chan notifier.Event, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, notifier.Sign("secret", r.Header.Get(notifier.TimestampHeader), body), r.Header.Get(notifier.SignatureHeader))
+
+			var event notifier.Event
+			assert.NoError(t, json.Unmarshal(body, &event))
+			received <- event
+		}))
+		defer server.Close()
+
+		n := notifier.NewWebhookNotifier(config.NotifierConfig{Webhooks: []config.WebhookConfig{{
+			Name:          "alerts",
+			URL:           server.URL,
+			Secret:        "secret",
+			ResourceTypes: []string{"pipelines"},
+			States:        []string{"STATE_ERROR"},
+			RetryBackoff:  1,
+		}}})
+		defer n.Close(context.Background())
+
+		n.Notify(context.Background(), notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			PreviousState:     "STATE_ACTIVE",
+			State:             "STATE_ERROR",
+		})
+
+		select {
+		case event := <-received:
+			assert.Equal(t, "resources/uid/types/pipelines", event.ResourcePermalink)
+			assert.Equal(t, "STATE_ACTIVE", event.PreviousState)
+			assert.Equal(t, int32(2), attempts.Load())
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook not delivered")
+		}
+	})
+
+	t.Run("filtered out", func(t *testing.T) {
+		var attempts atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+		}))
+		defer server.Close()
+
+		n := notifier.NewWebhookNotifier(config.NotifierConfig{Webhooks: []config.WebhookConfig{{
+			Name:   "alerts",
+			URL:    server.URL,
+			States: []string{"STATE_ERROR"},
+		}}})
+
+		n.Notify(context.Background(), notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			State:             "STATE_ACTIVE",
+		})
+
+		// the queued deliveries are done once closed
+		require.NoError(t, n.Close(context.Background()))
+		assert.Equal(t, int32(0), attempts.Load())
+	})
+
+	t.Run("drained on close", func(t *testing.T) {
+		var attempts atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+		}))
+		defer server.Close()
+
+		n := notifier.NewWebhookNotifier(config.NotifierConfig{
+			Webhooks: []config.WebhookConfig{{Name: "alerts", URL: server.URL}},
+			Workers:  1,
+		})
+
+		for i := 0; i < 5; i++ {
+			n.Notify(context.Background(), notifier.Event{
+				ResourcePermalink: "resources/uid/types/pipelines",
+				ResourceType:      "pipelines",
+				State:             "STATE_ACTIVE",
+			})
+		}
+
+		require.NoError(t, n.Close(context.Background()))
+		assert.Equal(t, int32(5), attempts.Load())
+
+		// the events notified once closed are dropped
+		n.Notify(context.Background(), notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			State:             "STATE_ACTIVE",
+		})
+		assert.Equal(t, int32(5), attempts.Load())
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		var attempts atomic.Int32
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			<-release
+		}))
+		defer server.Close()
+
+		n := notifier.NewWebhookNotifier(config.NotifierConfig{
+			Webhooks:  []config.WebhookConfig{{Name: "alerts", URL: server.URL}},
+			Workers:   1,
+			QueueSize: 1,
+		})
+
+		event := notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			State:             "STATE_ACTIVE",
+		}
+
+		// the worker blocks on the first delivery, the second one is queued
+		// and the third one dropped
+		n.Notify(context.Background(), event)
+		require.Eventually(t, func() bool { return attempts.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+		n.Notify(context.Background(), event)
+		n.Notify(context.Background(), event)
+
+		close(release)
+		require.NoError(t, n.Close(context.Background()))
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("cancelled after the drain deadline", func(t *testing.T) {
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		n := notifier.NewWebhookNotifier(config.NotifierConfig{
+			Webhooks: []config.WebhookConfig{{Name: "alerts", URL: server.URL}},
+		})
+
+		n.Notify(context.Background(), notifier.Event{
+			ResourcePermalink: "resources/uid/types/pipelines",
+			ResourceType:      "pipelines",
+			State:             "STATE_ACTIVE",
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, n.Close(ctx), context.DeadlineExceeded)
+	})
+}
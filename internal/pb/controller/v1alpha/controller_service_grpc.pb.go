@@ -0,0 +1,325 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: vdp/controller/v1alpha/controller_service.proto
+
+package controllerv1alpha
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ControllerPrivateService_Liveness_FullMethodName        = "/vdp.controller.v1alpha.ControllerPrivateService/Liveness"
+	ControllerPrivateService_Readiness_FullMethodName       = "/vdp.controller.v1alpha.ControllerPrivateService/Readiness"
+	ControllerPrivateService_GetResource_FullMethodName     = "/vdp.controller.v1alpha.ControllerPrivateService/GetResource"
+	ControllerPrivateService_UpdateResource_FullMethodName  = "/vdp.controller.v1alpha.ControllerPrivateService/UpdateResource"
+	ControllerPrivateService_DeleteResource_FullMethodName  = "/vdp.controller.v1alpha.ControllerPrivateService/DeleteResource"
+	ControllerPrivateService_GetSystemHealth_FullMethodName = "/vdp.controller.v1alpha.ControllerPrivateService/GetSystemHealth"
+)
+
+// ControllerPrivateServiceClient is the client API for ControllerPrivateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControllerPrivateServiceClient interface {
+	// Liveness method receives a LivenessRequest message and returns a
+	// LivenessResponse message.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+	Liveness(ctx context.Context, in *LivenessRequest, opts ...grpc.CallOption) (*LivenessResponse, error)
+	// Readiness method receives a ReadinessRequest message and returns a
+	// ReadinessResponse message.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+	Readiness(ctx context.Context, in *ReadinessRequest, opts ...grpc.CallOption) (*ReadinessResponse, error)
+	// GetResource method receives a GetResourceRequest message
+	// and returns a GetResourceResponse
+	GetResource(ctx context.Context, in *GetResourceRequest, opts ...grpc.CallOption) (*GetResourceResponse, error)
+	// UpdateResource method receives a UpdateResourceRequest message
+	// and returns a UpdateResourceResponse
+	UpdateResource(ctx context.Context, in *UpdateResourceRequest, opts ...grpc.CallOption) (*UpdateResourceResponse, error)
+	// DeleteResource method receives a DeleteResourceRequest message
+	// and returns a DeleteResourceResponse
+	DeleteResource(ctx context.Context, in *DeleteResourceRequest, opts ...grpc.CallOption) (*DeleteResourceResponse, error)
+	// GetSystemHealth method receives a GetSystemHealthRequest message and
+	// returns a GetSystemHealthResponse with the probed backend service states
+	// and the number of resources per state
+	GetSystemHealth(ctx context.Context, in *GetSystemHealthRequest, opts ...grpc.CallOption) (*GetSystemHealthResponse, error)
+}
+
+type controllerPrivateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControllerPrivateServiceClient(cc grpc.ClientConnInterface) ControllerPrivateServiceClient {
+	return &controllerPrivateServiceClient{cc}
+}
+
+func (c *controllerPrivateServiceClient) Liveness(ctx context.Context, in *LivenessRequest, opts ...grpc.CallOption) (*LivenessResponse, error) {
+	out := new(LivenessResponse)
+	err := c.cc.Invoke(ctx, ControllerPrivateService_Liveness_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerPrivateServiceClient) Readiness(ctx context.Context, in *ReadinessRequest, opts ...grpc.CallOption) (*ReadinessResponse, error) {
+	out := new(ReadinessResponse)
+	err := c.cc.Invoke(ctx, ControllerPrivateService_Readiness_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerPrivateServiceClient) GetResource(ctx context.Context, in *GetResourceRequest, opts ...grpc.CallOption) (*GetResourceResponse, error) {
+	out := new(GetResourceResponse)
+	err := c.cc.Invoke(ctx, ControllerPrivateService_GetResource_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerPrivateServiceClient) UpdateResource(ctx context.Context, in *UpdateResourceRequest, opts ...grpc.CallOption) (*UpdateResourceResponse, error) {
+	out := new(UpdateResourceResponse)
+	err := c.cc.Invoke(ctx, ControllerPrivateService_UpdateResource_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerPrivateServiceClient) DeleteResource(ctx context.Context, in *DeleteResourceRequest, opts ...grpc.CallOption) (*DeleteResourceResponse, error) {
+	out := new(DeleteResourceResponse)
+	err := c.cc.Invoke(ctx, ControllerPrivateService_DeleteResource_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerPrivateServiceClient) GetSystemHealth(ctx context.Context, in *GetSystemHealthRequest, opts ...grpc.CallOption) (*GetSystemHealthResponse, error) {
+	out := new(GetSystemHealthResponse)
+	err := c.cc.Invoke(ctx, ControllerPrivateService_GetSystemHealth_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ControllerPrivateServiceServer is the server API for ControllerPrivateService service.
+// All implementations must embed UnimplementedControllerPrivateServiceServer
+// for forward compatibility
+type ControllerPrivateServiceServer interface {
+	// Liveness method receives a LivenessRequest message and returns a
+	// LivenessResponse message.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+	Liveness(context.Context, *LivenessRequest) (*LivenessResponse, error)
+	// Readiness method receives a ReadinessRequest message and returns a
+	// ReadinessResponse message.
+	// See https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+	Readiness(context.Context, *ReadinessRequest) (*ReadinessResponse, error)
+	// GetResource method receives a GetResourceRequest message
+	// and returns a GetResourceResponse
+	GetResource(context.Context, *GetResourceRequest) (*GetResourceResponse, error)
+	// UpdateResource method receives a UpdateResourceRequest message
+	// and returns a UpdateResourceResponse
+	UpdateResource(context.Context, *UpdateResourceRequest) (*UpdateResourceResponse, error)
+	// DeleteResource method receives a DeleteResourceRequest message
+	// and returns a DeleteResourceResponse
+	DeleteResource(context.Context, *DeleteResourceRequest) (*DeleteResourceResponse, error)
+	// GetSystemHealth method receives a GetSystemHealthRequest message and
+	// returns a GetSystemHealthResponse with the probed backend service states
+	// and the number of resources per state
+	GetSystemHealth(context.Context, *GetSystemHealthRequest) (*GetSystemHealthResponse, error)
+	mustEmbedUnimplementedControllerPrivateServiceServer()
+}
+
+// UnimplementedControllerPrivateServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedControllerPrivateServiceServer struct {
+}
+
+func (UnimplementedControllerPrivateServiceServer) Liveness(context.Context, *LivenessRequest) (*LivenessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Liveness not implemented")
+}
+func (UnimplementedControllerPrivateServiceServer) Readiness(context.Context, *ReadinessRequest) (*ReadinessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Readiness not implemented")
+}
+func (UnimplementedControllerPrivateServiceServer) GetResource(context.Context, *GetResourceRequest) (*GetResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResource not implemented")
+}
+func (UnimplementedControllerPrivateServiceServer) UpdateResource(context.Context, *UpdateResourceRequest) (*UpdateResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateResource not implemented")
+}
+func (UnimplementedControllerPrivateServiceServer) DeleteResource(context.Context, *DeleteResourceRequest) (*DeleteResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteResource not implemented")
+}
+func (UnimplementedControllerPrivateServiceServer) GetSystemHealth(context.Context, *GetSystemHealthRequest) (*GetSystemHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSystemHealth not implemented")
+}
+func (UnimplementedControllerPrivateServiceServer) mustEmbedUnimplementedControllerPrivateServiceServer() {
+}
+
+// UnsafeControllerPrivateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControllerPrivateServiceServer will
+// result in compilation errors.
+type UnsafeControllerPrivateServiceServer interface {
+	mustEmbedUnimplementedControllerPrivateServiceServer()
+}
+
+func RegisterControllerPrivateServiceServer(s grpc.ServiceRegistrar, srv ControllerPrivateServiceServer) {
+	s.RegisterService(&ControllerPrivateService_ServiceDesc, srv)
+}
+
+func _ControllerPrivateService_Liveness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LivenessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerPrivateServiceServer).Liveness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerPrivateService_Liveness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerPrivateServiceServer).Liveness(ctx, req.(*LivenessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerPrivateService_Readiness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadinessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerPrivateServiceServer).Readiness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerPrivateService_Readiness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerPrivateServiceServer).Readiness(ctx, req.(*ReadinessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerPrivateService_GetResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerPrivateServiceServer).GetResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerPrivateService_GetResource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerPrivateServiceServer).GetResource(ctx, req.(*GetResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerPrivateService_UpdateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerPrivateServiceServer).UpdateResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerPrivateService_UpdateResource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerPrivateServiceServer).UpdateResource(ctx, req.(*UpdateResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerPrivateService_DeleteResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerPrivateServiceServer).DeleteResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerPrivateService_DeleteResource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerPrivateServiceServer).DeleteResource(ctx, req.(*DeleteResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControllerPrivateService_GetSystemHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSystemHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerPrivateServiceServer).GetSystemHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControllerPrivateService_GetSystemHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerPrivateServiceServer).GetSystemHealth(ctx, req.(*GetSystemHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ControllerPrivateService_ServiceDesc is the grpc.ServiceDesc for ControllerPrivateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ControllerPrivateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vdp.controller.v1alpha.ControllerPrivateService",
+	HandlerType: (*ControllerPrivateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Liveness",
+			Handler:    _ControllerPrivateService_Liveness_Handler,
+		},
+		{
+			MethodName: "Readiness",
+			Handler:    _ControllerPrivateService_Readiness_Handler,
+		},
+		{
+			MethodName: "GetResource",
+			Handler:    _ControllerPrivateService_GetResource_Handler,
+		},
+		{
+			MethodName: "UpdateResource",
+			Handler:    _ControllerPrivateService_UpdateResource_Handler,
+		},
+		{
+			MethodName: "DeleteResource",
+			Handler:    _ControllerPrivateService_DeleteResource_Handler,
+		},
+		{
+			MethodName: "GetSystemHealth",
+			Handler:    _ControllerPrivateService_GetSystemHealth_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vdp/controller/v1alpha/controller_service.proto",
+}
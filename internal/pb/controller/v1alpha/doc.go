@@ -0,0 +1,5 @@
+// Package controllerv1alpha is the controller API generated from the protos in
+// integration-test/proto, ahead of their release in protogen-go
+package controllerv1alpha
+
+//go:generate ./generate.sh
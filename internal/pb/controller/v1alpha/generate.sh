@@ -0,0 +1,42 @@
+#!/bin/sh
+#
+# Generates the controller API from the protos in integration-test/proto, which
+# import each other by relative paths for the k6 tests. The protos are copied
+# with their imports rooted at vdp/, the controller protos are generated here
+# and the others are mapped to their packages in protogen-go.
+#
+# Requires protoc, protoc-gen-go, protoc-gen-go-grpc and protoc-gen-grpc-gateway
+# in PATH, and the googleapis and protoc-gen-openapiv2 protos in PROTO_INCLUDE.
+
+set -e
+
+module=github.com/instill-ai/controller
+root=$(cd "$(dirname "$0")/../../../.." && pwd)
+work=$(mktemp -d)
+trap 'rm -rf "$work"' EXIT
+
+cd "$root/integration-test/proto"
+
+params="module=$module"
+for file in $(find vdp -name '*.proto' | sort); do
+	dir=$(dirname "$file")
+	mkdir -p "$work/$dir"
+	sed \
+		-e "s|import \"\./\([a-z_]*\.proto\)\";|import \"$dir/\1\";|" \
+		-e 's|import "\.\./\.\./\([a-z]*\)/v1alpha/\([a-z_]*\.proto\)";|import "vdp/\1/v1alpha/\2";|' \
+		"$file" >"$work/$file"
+
+	name=$(basename "$(dirname "$dir")")
+	case "$name" in
+	controller) pkg="$module/internal/pb/controller/v1alpha;controllerv1alpha" ;;
+	*) pkg="github.com/instill-ai/protogen-go/$dir;${name}v1alpha" ;;
+	esac
+	params="$params,M$file=$pkg"
+done
+
+cd "$work"
+protoc -I . -I "${PROTO_INCLUDE:?PROTO_INCLUDE must point to the googleapis and protoc-gen-openapiv2 protos}" \
+	--go_out="$root" --go_opt="$params" \
+	--go-grpc_out="$root" --go-grpc_opt="$params" \
+	--grpc-gateway_out="$root" --grpc-gateway_opt="$params,omit_package_doc=true" \
+	vdp/controller/v1alpha/*.proto
@@ -9,3 +9,11 @@ const (
 )
 
 const DefaultPageSize = 10
+
+const (
+	SERVICE_TRITON_SERVER     = "triton-server"
+	SERVICE_CONNECTOR_BACKEND = "connector-backend"
+	SERVICE_MODEL_BACKEND     = "model-backend"
+	SERVICE_PIPELINE_BACKEND  = "pipeline-backend"
+	SERVICE_MGMT_BACKEND      = "mgmt-backend"
+)
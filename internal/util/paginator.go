@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListFunc lists a page of items from a backend, returning the items and the
+// token of the next page, which is empty on the last page
+type ListFunc[T any] func(ctx context.Context, pageSize int64, pageToken string) ([]T, string, error)
+
+// Paginate lists every page with list, following the next page tokens until
+// the backend returns an empty one, and passes each page to handle as soon as
+// it is received
+func Paginate[T any](ctx context.Context, pageSize int64, list ListFunc[T], handle func(items []T)) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	pageToken := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		items, nextPageToken, err := list(ctx, pageSize, pageToken)
+		if err != nil {
+			return err
+		}
+
+		handle(items)
+
+		if nextPageToken == "" {
+			return nil
+		}
+		if nextPageToken == pageToken {
+			return fmt.Errorf("pagination did not advance past page token %s", pageToken)
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// PageTokenOrNil returns nil for the empty page token of the first page
+func PageTokenOrNil(pageToken string) *string {
+	if pageToken == "" {
+		return nil
+	}
+	return &pageToken
+}
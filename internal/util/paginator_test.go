@@ -0,0 +1,50 @@
+package util_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/instill-ai/controller/internal/util"
+)
+
+func TestPaginate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("follows next page tokens", func(t *testing.T) {
+		pages := map[string][]int{"": {1, 2}, "a": {3, 4}, "b": {5}}
+		next := map[string]string{"": "a", "a": "b", "b": ""}
+
+		var items []int
+		err := util.Paginate(ctx, 2, func(ctx context.Context, pageSize int64, pageToken string) ([]int, string, error) {
+			assert.Equal(t, int64(2), pageSize)
+			return pages[pageToken], next[pageToken], nil
+		}, func(page []int) {
+			items = append(items, page...)
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, items)
+	})
+
+	t.Run("stops on a repeated page token", func(t *testing.T) {
+		err := util.Paginate(ctx, 0, func(ctx context.Context, pageSize int64, pageToken string) ([]int, string, error) {
+			assert.Equal(t, int64(util.DefaultPageSize), pageSize)
+			return []int{1}, "a", nil
+		}, func(page []int) {})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns list errors", func(t *testing.T) {
+		err := util.Paginate(ctx, 1, func(ctx context.Context, pageSize int64, pageToken string) ([]int, string, error) {
+			return nil, "", fmt.Errorf("unavailable")
+		}, func(page []int) {
+			t.Fail()
+		})
+
+		assert.Error(t, err)
+	})
+}
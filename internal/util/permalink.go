@@ -0,0 +1,76 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// ResourcePermalink identifies a resource in the controller, formatted as
+// resources/<uid>/types/<type>
+type ResourcePermalink struct {
+	// UID is the UUID of the resource, or the name of a backend service
+	UID  string
+	Type string
+}
+
+// InvalidPermalinkError tells why a resource permalink is rejected
+type InvalidPermalinkError struct {
+	Permalink string
+	Reason    string
+}
+
+func (e *InvalidPermalinkError) Error() string {
+	return fmt.Sprintf("invalid resource permalink %q: %s", e.Permalink, e.Reason)
+}
+
+// NewResourcePermalink returns the permalink of a resource
+func NewResourcePermalink(uid string, resourceType string) ResourcePermalink {
+	return ResourcePermalink{UID: uid, Type: resourceType}
+}
+
+// ParseResourcePermalink parses and validates a resource permalink, whose UID
+// must be a UUID unless it is the one of a backend service
+func ParseResourcePermalink(permalink string) (ResourcePermalink, error) {
+	invalid := func(reason string) (ResourcePermalink, error) {
+		return ResourcePermalink{}, &InvalidPermalinkError{Permalink: permalink, Reason: reason}
+	}
+
+	parts := strings.Split(permalink, "/")
+	if len(parts) != 4 || parts[0] != "resources" || parts[2] != "types" {
+		return invalid("expected resources/<uid>/types/<type>")
+	}
+
+	uid, resourceType := parts[1], parts[3]
+
+	switch resourceType {
+	case RESOURCE_TYPE_MODEL, RESOURCE_TYPE_PIPELINE, RESOURCE_TYPE_SOURCE_CONNECTOR, RESOURCE_TYPE_DESTINATION_CONNECTOR:
+		id, err := uuid.FromString(uid)
+		if err != nil || !strings.EqualFold(id.String(), uid) {
+			return invalid(fmt.Sprintf("uid %q is not a UUID", uid))
+		}
+	case RESOURCE_TYPE_SERVICE:
+		if uid == "" {
+			return invalid("empty service name")
+		}
+	default:
+		return invalid(fmt.Sprintf("unknown resource type %q", resourceType))
+	}
+
+	return NewResourcePermalink(uid, resourceType), nil
+}
+
+// ParseRequestPermalink parses the resource permalink of a request field,
+// returning an InvalidArgument error with the field violation if malformed
+func ParseRequestPermalink(field string, permalink string) (ResourcePermalink, error) {
+	p, err := ParseResourcePermalink(permalink)
+	if err != nil {
+		return ResourcePermalink{}, NewInvalidArgumentError(field, err.Error())
+	}
+	return p, nil
+}
+
+func (p ResourcePermalink) String() string {
+	return fmt.Sprintf("resources/%s/types/%s", p.UID, p.Type)
+}
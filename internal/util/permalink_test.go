@@ -0,0 +1,59 @@
+package util_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/controller/internal/util"
+)
+
+func TestParseResourcePermalink(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, permalink := range []string{
+			"resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/models",
+			"resources/0D5A4AB0-5E32-4A4C-8E6A-1E1AC2B4ED35/types/pipelines",
+			"resources/model-backend/types/services",
+		} {
+			p, err := util.ParseResourcePermalink(permalink)
+			assert.NoError(t, err, permalink)
+			assert.Equal(t, permalink, p.String())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, permalink := range []string{
+			"",
+			"resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35",
+			"resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/models/workflow",
+			"resource/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/models",
+			"resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/kinds/models",
+			"resources/name/types/models",
+			"resources/0d5a4ab05e324a4c8e6a1e1ac2b4ed35/types/models",
+			"resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/operators",
+			"resources//types/services",
+		} {
+			_, err := util.ParseResourcePermalink(permalink)
+
+			var invalid *util.InvalidPermalinkError
+			assert.True(t, errors.As(err, &invalid), permalink)
+		}
+	})
+}
+
+func TestParseRequestPermalink(t *testing.T) {
+	_, err := util.ParseRequestPermalink("resource.resource_permalink", "resources/name/types/models")
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		if assert.True(t, ok) {
+			assert.Equal(t, "resource.resource_permalink", badRequest.GetFieldViolations()[0].GetField())
+		}
+	}
+}
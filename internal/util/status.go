@@ -0,0 +1,22 @@
+package util
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewInvalidArgumentError returns an InvalidArgument error with the violation
+// of a request field in its details
+func NewInvalidArgumentError(field string, description string) error {
+	st := status.New(codes.InvalidArgument, description)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: description,
+		}},
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
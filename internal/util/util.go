@@ -5,15 +5,11 @@ import (
 )
 
 func ConvertUIDToResourcePermalink(uid string, resourceType string) string {
-	resourceName := fmt.Sprintf("resources/%s/types/%s", uid, resourceType)
-
-	return resourceName
+	return NewResourcePermalink(uid, resourceType).String()
 }
 
 func ConvertServiceToResourceName(serviceName string) string {
-	resourceName := fmt.Sprintf("resources/%s/types/%s", serviceName, RESOURCE_TYPE_SERVICE)
-
-	return resourceName
+	return NewResourcePermalink(serviceName, RESOURCE_TYPE_SERVICE).String()
 }
 
 func ConvertResourcePermalinkToWorkflowName(resourcePermalink string) string {
@@ -21,3 +17,15 @@ func ConvertResourcePermalinkToWorkflowName(resourcePermalink string) string {
 
 	return resourceWorkflowId
 }
+
+func ConvertResourcePermalinkToDesiredStateName(resourcePermalink string) string {
+	resourceDesiredState := fmt.Sprintf("%s/desired", resourcePermalink)
+
+	return resourceDesiredState
+}
+
+func ConvertResourcePermalinkToConditionsName(resourcePermalink string) string {
+	resourceConditions := fmt.Sprintf("%s/conditions", resourcePermalink)
+
+	return resourceConditions
+}
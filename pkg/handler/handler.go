@@ -2,12 +2,19 @@ package handler
 
 import (
 	"context"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/util"
 	"github.com/instill-ai/controller/pkg/logger"
 	"github.com/instill-ai/controller/pkg/service"
 
@@ -37,8 +44,24 @@ func (h *PrivateHandler) Liveness(ctx context.Context, in *controllerPB.Liveness
 
 }
 
-// Readiness checks the readiness of the server
+// Readiness checks the readiness of the server, reporting NOT_SERVING with the
+// reason when etcd or the control loop is unhealthy, and a 503 on the HTTP
+// gateway through the x-http-code header
 func (h *PrivateHandler) Readiness(ctx context.Context, in *controllerPB.ReadinessRequest) (*controllerPB.ReadinessResponse, error) {
+	if err := h.service.CheckHealth(ctx); err != nil {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(
+			"x-http-code", strconv.Itoa(http.StatusServiceUnavailable),
+		)); err != nil {
+			return nil, err
+		}
+		return &controllerPB.ReadinessResponse{
+			HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
+				Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
+			},
+			Reason: err.Error(),
+		}, nil
+	}
+
 	return &controllerPB.ReadinessResponse{
 		HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
 			Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
@@ -54,11 +77,17 @@ func (h *PrivateHandler) GetResource(ctx context.Context, req *controllerPB.GetR
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	resource, err := h.service.GetResourceState(ctx, req.ResourcePermalink)
+	if _, err := util.ParseRequestPermalink("resource_permalink", req.ResourcePermalink); err != nil {
+		return nil, err
+	}
+
+	record, err := h.service.GetResourceRecord(ctx, req.ResourcePermalink)
 	if err != nil {
 		return nil, err
 	}
 
+	resource := record.Observed
+
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		false,
@@ -69,11 +98,42 @@ func (h *PrivateHandler) GetResource(ctx context.Context, req *controllerPB.GetR
 		custom_otel.SetEventResource(resource),
 	)))
 
+	// the desired state is only known for the resources the user can turn on
+	// and off
 	return &controllerPB.GetResourceResponse{
-		Resource: resource,
+		Resource:        resource,
+		DesiredResource: record.Desired,
+		InSync:          record.InSync(),
+		Conditions:      conditionsToPB(record.Conditions),
 	}, nil
 }
 
+// conditionsToPB converts the conditions of a resource record to their API
+// representation
+func conditionsToPB(conditions []service.Condition) []*controllerPB.Condition {
+	pbConditions := make([]*controllerPB.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		status := controllerPB.Condition_STATUS_UNSPECIFIED
+		switch c.Status {
+		case service.ConditionTrue:
+			status = controllerPB.Condition_STATUS_TRUE
+		case service.ConditionFalse:
+			status = controllerPB.Condition_STATUS_FALSE
+		case service.ConditionUnknown:
+			status = controllerPB.Condition_STATUS_UNKNOWN
+		}
+
+		pbConditions = append(pbConditions, &controllerPB.Condition{
+			Type:               string(c.Type),
+			Status:             status,
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: timestamppb.New(c.LastTransitionTime),
+		})
+	}
+	return pbConditions
+}
+
 func (h *PrivateHandler) UpdateResource(ctx context.Context, req *controllerPB.UpdateResourceRequest) (*controllerPB.UpdateResourceResponse, error) {
 
 	ctx, span := tracer.Start(ctx, "UpdateResource",
@@ -82,6 +142,10 @@ func (h *PrivateHandler) UpdateResource(ctx context.Context, req *controllerPB.U
 
 	logger, _ := logger.GetZapLogger(ctx)
 
+	if err := validateResource(req.Resource, req.WorkflowId); err != nil {
+		return nil, err
+	}
+
 	if req.WorkflowId != nil {
 		err := h.service.UpdateResourceWorkflowId(ctx, req.Resource.ResourcePermalink, *req.WorkflowId)
 
@@ -117,6 +181,10 @@ func (h *PrivateHandler) DeleteResource(ctx context.Context, req *controllerPB.D
 
 	logger, _ := logger.GetZapLogger(ctx)
 
+	if _, err := util.ParseRequestPermalink("resource_permalink", req.ResourcePermalink); err != nil {
+		return nil, err
+	}
+
 	if err := h.service.DeleteResourceState(ctx, req.ResourcePermalink); err != nil {
 		return nil, err
 	}
@@ -125,6 +193,14 @@ func (h *PrivateHandler) DeleteResource(ctx context.Context, req *controllerPB.D
 		return nil, err
 	}
 
+	if err := h.service.DeleteResourceDesiredState(ctx, req.ResourcePermalink); err != nil {
+		return nil, err
+	}
+
+	if err := h.service.DeleteResourceConditions(ctx, req.ResourcePermalink); err != nil {
+		return nil, err
+	}
+
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		false,
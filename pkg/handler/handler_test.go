@@ -0,0 +1,196 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/pkg/handler"
+	"github.com/instill-ai/controller/pkg/service"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+// fakeService implements the service methods the handlers under test call,
+// the other ones panicking
+type fakeService struct {
+	service.Service
+	health       error
+	systemHealth *service.SystemHealth
+	record       *service.ResourceRecord
+}
+
+func (s *fakeService) GetResourceRecord(ctx context.Context, resourcePermalink string) (*service.ResourceRecord, error) {
+	return s.record, nil
+}
+
+func (s *fakeService) UpdateResourceState(ctx context.Context, resource *controllerPB.Resource) error {
+	return nil
+}
+
+func (s *fakeService) UpdateResourceWorkflowId(ctx context.Context, resourcePermalink string, workflowId string) error {
+	return nil
+}
+
+func (s *fakeService) CheckHealth(ctx context.Context) error {
+	return s.health
+}
+
+func (s *fakeService) GetSystemHealth(ctx context.Context) (*service.SystemHealth, error) {
+	return s.systemHealth, nil
+}
+
+// headerStream records the headers set by a handler
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string {
+	return ""
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *headerStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
+func TestReadiness(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		stream := &headerStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+		h := handler.NewPrivateHandler(&fakeService{})
+
+		resp, err := h.Readiness(ctx, &controllerPB.ReadinessRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, resp.GetHealthCheckResponse().GetStatus())
+		assert.Empty(t, resp.GetReason())
+		assert.Empty(t, stream.header.Get("x-http-code"))
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		stream := &headerStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+		h := handler.NewPrivateHandler(&fakeService{health: errors.New("etcd connection is not ready")})
+
+		resp, err := h.Readiness(ctx, &controllerPB.ReadinessRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, resp.GetHealthCheckResponse().GetStatus())
+		assert.Equal(t, "etcd connection is not ready", resp.GetReason())
+		assert.Equal(t, []string{"503"}, stream.header.Get("x-http-code"))
+	})
+}
+
+func TestGetSystemHealth(t *testing.T) {
+	h := handler.NewPrivateHandler(&fakeService{systemHealth: &service.SystemHealth{
+		Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
+		Services: []*controllerPB.Resource{{
+			ResourcePermalink: "resources/model-backend/types/services",
+			State: &controllerPB.Resource_BackendState{
+				BackendState: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
+			},
+		}},
+		Models: map[modelPB.Model_State]int64{
+			modelPB.Model_STATE_ERROR:  1,
+			modelPB.Model_STATE_ONLINE: 2,
+		},
+		SourceConnectors:      map[connectorPB.Connector_State]int64{},
+		DestinationConnectors: map[connectorPB.Connector_State]int64{connectorPB.Connector_STATE_CONNECTED: 3},
+		Pipelines:             map[pipelinePB.Pipeline_State]int64{},
+	}})
+
+	resp, err := h.GetSystemHealth(context.Background(), &controllerPB.GetSystemHealthRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, resp.GetStatus())
+	assert.Len(t, resp.GetServices(), 1)
+	// the counts are ordered by state
+	require.Len(t, resp.GetModels(), 2)
+	assert.Equal(t, modelPB.Model_STATE_ONLINE, resp.GetModels()[0].GetState())
+	assert.Equal(t, int64(2), resp.GetModels()[0].GetCount())
+	assert.Equal(t, modelPB.Model_STATE_ERROR, resp.GetModels()[1].GetState())
+	assert.Empty(t, resp.GetSourceConnectors())
+	require.Len(t, resp.GetDestinationConnectors(), 1)
+	assert.Equal(t, int64(3), resp.GetDestinationConnectors()[0].GetCount())
+	assert.Empty(t, resp.GetPipelines())
+}
+
+func TestGetResource(t *testing.T) {
+	const permalink = "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/models"
+
+	t.Run("desired state", func(t *testing.T) {
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), &headerStream{})
+
+		h := handler.NewPrivateHandler(&fakeService{record: &service.ResourceRecord{
+			Observed: &controllerPB.Resource{
+				ResourcePermalink: permalink,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ERROR},
+			},
+			Desired: &controllerPB.Resource{
+				ResourcePermalink: permalink,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ONLINE},
+			},
+			Conditions: []service.Condition{{
+				Type:               service.ConditionBackendReachable,
+				Status:             service.ConditionFalse,
+				Reason:             "ProbeFailed",
+				Message:            "connection refused",
+				LastTransitionTime: time.Unix(1700000000, 0),
+			}},
+		}})
+
+		resp, err := h.GetResource(ctx, &controllerPB.GetResourceRequest{ResourcePermalink: permalink})
+
+		require.NoError(t, err)
+		assert.Equal(t, modelPB.Model_STATE_ERROR, resp.GetResource().GetModelState())
+		assert.Equal(t, modelPB.Model_STATE_ONLINE, resp.GetDesiredResource().GetModelState())
+		assert.False(t, resp.GetInSync())
+		require.Len(t, resp.GetConditions(), 1)
+		assert.Equal(t, "BackendReachable", resp.GetConditions()[0].GetType())
+		assert.Equal(t, controllerPB.Condition_STATUS_FALSE, resp.GetConditions()[0].GetStatus())
+		assert.Equal(t, "ProbeFailed", resp.GetConditions()[0].GetReason())
+		assert.Equal(t, "connection refused", resp.GetConditions()[0].GetMessage())
+		assert.Equal(t, int64(1700000000), resp.GetConditions()[0].GetLastTransitionTime().GetSeconds())
+	})
+
+	t.Run("no desired state", func(t *testing.T) {
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), &headerStream{})
+
+		h := handler.NewPrivateHandler(&fakeService{record: &service.ResourceRecord{
+			Observed: &controllerPB.Resource{
+				ResourcePermalink: "resources/model-backend/types/services",
+				State: &controllerPB.Resource_BackendState{
+					BackendState: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
+				},
+			},
+		}})
+
+		resp, err := h.GetResource(ctx, &controllerPB.GetResourceRequest{ResourcePermalink: "resources/model-backend/types/services"})
+
+		require.NoError(t, err)
+		assert.Nil(t, resp.GetDesiredResource())
+		assert.True(t, resp.GetInSync())
+		assert.Empty(t, resp.GetConditions())
+	})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/health"
+
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/pkg/logger"
+	"github.com/instill-ai/controller/pkg/service"
+)
+
+// NewHealthServer returns a grpc.health.v1 server whose serving status follows
+// the controller health, refreshed every interval until ctx is done
+func NewHealthServer(ctx context.Context, s service.Service, interval time.Duration) *health.Server {
+	healthServer := health.NewServer()
+
+	current := healthgrpc.HealthCheckResponse_UNKNOWN
+	update := func() {
+		logger, _ := logger.GetZapLogger(ctx)
+
+		status := healthgrpc.HealthCheckResponse_SERVING
+		err := s.CheckHealth(ctx)
+		if err != nil {
+			status = healthgrpc.HealthCheckResponse_NOT_SERVING
+		}
+
+		if status != current {
+			if err != nil {
+				logger.Warn(fmt.Sprintf("[controller] health status changed to %v: %v", status, err))
+			} else {
+				logger.Info(fmt.Sprintf("[controller] health status changed to %v", status))
+			}
+			current = status
+		}
+
+		healthServer.SetServingStatus("", status)
+		healthServer.SetServingStatus(controllerPB.ControllerPrivateService_ServiceDesc.ServiceName, status)
+	}
+
+	update()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				healthServer.Shutdown()
+				return
+			case <-ticker.C:
+				update()
+			}
+		}
+	}()
+
+	return healthServer
+}
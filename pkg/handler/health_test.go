@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/pkg/handler"
+)
+
+func TestHealthServer(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		health error
+		status healthgrpc.HealthCheckResponse_ServingStatus
+	}{
+		{name: "serving", status: healthgrpc.HealthCheckResponse_SERVING},
+		{name: "not serving", health: errors.New("etcd connection is not ready"), status: healthgrpc.HealthCheckResponse_NOT_SERVING},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			listener := bufconn.Listen(1 << 20)
+			grpcS := grpc.NewServer()
+			healthgrpc.RegisterHealthServer(grpcS, handler.NewHealthServer(ctx, &fakeService{health: tc.health}, time.Minute))
+			go func() {
+				_ = grpcS.Serve(listener)
+			}()
+			defer grpcS.Stop()
+
+			conn, err := grpc.Dial("bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return listener.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			client := healthgrpc.NewHealthClient(conn)
+
+			// the server as a whole and the controller service report the
+			// controller health
+			for _, service := range []string{"", controllerPB.ControllerPrivateService_ServiceDesc.ServiceName} {
+				resp, err := client.Check(ctx, &healthgrpc.HealthCheckRequest{Service: service})
+				require.NoError(t, err)
+				assert.Equal(t, tc.status, resp.GetStatus())
+			}
+
+			_, err = client.Check(ctx, &healthgrpc.HealthCheckRequest{Service: "unknown"})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	}
+}
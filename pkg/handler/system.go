@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/trace"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/pkg/logger"
+
+	custom_otel "github.com/instill-ai/controller/pkg/logger/otel"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+// GetSystemHealth returns the probed backend service states, the number of
+// resources per state and the rolled-up platform status
+func (h *PrivateHandler) GetSystemHealth(ctx context.Context, req *controllerPB.GetSystemHealthRequest) (*controllerPB.GetSystemHealthResponse, error) {
+
+	ctx, span := tracer.Start(ctx, "GetSystemHealth",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	systemHealth, err := h.service.GetSystemHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &controllerPB.GetSystemHealthResponse{
+		Status:   systemHealth.Status,
+		Services: systemHealth.Services,
+	}
+	for _, state := range sortedStates(systemHealth.Models) {
+		resp.Models = append(resp.Models, &controllerPB.ModelStateCount{State: state, Count: systemHealth.Models[state]})
+	}
+	for _, state := range sortedStates(systemHealth.SourceConnectors) {
+		resp.SourceConnectors = append(resp.SourceConnectors, &controllerPB.ConnectorStateCount{State: state, Count: systemHealth.SourceConnectors[state]})
+	}
+	for _, state := range sortedStates(systemHealth.DestinationConnectors) {
+		resp.DestinationConnectors = append(resp.DestinationConnectors, &controllerPB.ConnectorStateCount{State: state, Count: systemHealth.DestinationConnectors[state]})
+	}
+	for _, state := range sortedStates(systemHealth.Pipelines) {
+		resp.Pipelines = append(resp.Pipelines, &controllerPB.PipelineStateCount{State: state, Count: systemHealth.Pipelines[state]})
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		false,
+		"GetSystemHealth",
+		"request",
+		"GetSystemHealth done",
+		false,
+		custom_otel.SetEventResult(systemHealth.Status.String()),
+	)))
+
+	return resp, nil
+}
+
+// sortedStates returns the states counted, in the order of their values
+func sortedStates[T modelPB.Model_State | connectorPB.Connector_State | pipelinePB.Pipeline_State](counts map[T]int64) []T {
+	states := make([]T, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i] < states[j]
+	})
+	return states
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/util"
+)
+
+// validateResource checks that the state of an updated resource is a defined
+// value of the state of its type, and that only the resources updated by
+// long-running operations come with a workflow
+func validateResource(resource *controllerPB.Resource, workflowId *string) error {
+	if resource == nil {
+		return util.NewInvalidArgumentError("resource", "resource is required")
+	}
+
+	p, err := util.ParseRequestPermalink("resource.resource_permalink", resource.ResourcePermalink)
+	if err != nil {
+		return err
+	}
+
+	var field string
+	var types []string
+	var state protoreflect.Enum
+	switch s := resource.State.(type) {
+	case *controllerPB.Resource_ModelState:
+		field, types, state = "resource.model_state", []string{util.RESOURCE_TYPE_MODEL}, s.ModelState
+	case *controllerPB.Resource_PipelineState:
+		field, types, state = "resource.pipeline_state", []string{util.RESOURCE_TYPE_PIPELINE}, s.PipelineState
+	case *controllerPB.Resource_ConnectorState:
+		field, types, state = "resource.connector_state", []string{util.RESOURCE_TYPE_SOURCE_CONNECTOR, util.RESOURCE_TYPE_DESTINATION_CONNECTOR}, s.ConnectorState
+	case *controllerPB.Resource_BackendState:
+		field, types, state = "resource.backend_state", []string{util.RESOURCE_TYPE_SERVICE}, s.BackendState
+	default:
+		return util.NewInvalidArgumentError("resource.state", "resource state is required")
+	}
+
+	if !contains(types, p.Type) {
+		return util.NewInvalidArgumentError(field, fmt.Sprintf("%s cannot be set on %s", field, p.Type))
+	}
+	if state.Descriptor().Values().ByNumber(state.Number()) == nil {
+		return util.NewInvalidArgumentError(field, fmt.Sprintf("undefined %s value %d", field, state.Number()))
+	}
+
+	// models are the only resources with operations to follow
+	if workflowId != nil && p.Type != util.RESOURCE_TYPE_MODEL {
+		return util.NewInvalidArgumentError("workflow_id", fmt.Sprintf("workflow not supported for %s", p.Type))
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/pkg/handler"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+const (
+	modelPermalink     = "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/models"
+	pipelinePermalink  = "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/pipelines"
+	connectorPermalink = "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/destination-connectors"
+	servicePermalink   = "resources/model-backend/types/services"
+)
+
+// assertInvalidArgument checks that an error is InvalidArgument with a
+// violation of the given field
+func assertInvalidArgument(t *testing.T, err error, field string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status error: %v", err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.GetFieldViolations(), 1)
+	assert.Equal(t, field, badRequest.GetFieldViolations()[0].GetField())
+}
+
+func TestPermalinkValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		permalink string
+	}{
+		{name: "empty", permalink: ""},
+		{name: "missing type", permalink: "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35"},
+		{name: "wrong prefix", permalink: "resource/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/models"},
+		{name: "wrong separator", permalink: "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/type/models"},
+		{name: "trailing segment", permalink: modelPermalink + "/state"},
+		{name: "uid not a UUID", permalink: "resources/my-model/types/models"},
+		{name: "empty service name", permalink: "resources//types/services"},
+		{name: "unknown resource type", permalink: "resources/0d5a4ab0-5e32-4a4c-8e6a-1e1ac2b4ed35/types/datasets"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := handler.NewPrivateHandler(&fakeService{})
+
+			_, err := h.GetResource(context.Background(), &controllerPB.GetResourceRequest{ResourcePermalink: tc.permalink})
+			assertInvalidArgument(t, err, "resource_permalink")
+
+			_, err = h.DeleteResource(context.Background(), &controllerPB.DeleteResourceRequest{ResourcePermalink: tc.permalink})
+			assertInvalidArgument(t, err, "resource_permalink")
+
+			_, err = h.UpdateResource(context.Background(), &controllerPB.UpdateResourceRequest{Resource: &controllerPB.Resource{
+				ResourcePermalink: tc.permalink,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ONLINE},
+			}})
+			assertInvalidArgument(t, err, "resource.resource_permalink")
+		})
+	}
+}
+
+func TestResourceValidation(t *testing.T) {
+	workflowId := "workflow-id"
+
+	for _, tc := range []struct {
+		name       string
+		resource   *controllerPB.Resource
+		workflowId *string
+		// field is the field violated, none if valid
+		field string
+	}{
+		{
+			name:  "missing resource",
+			field: "resource",
+		},
+		{
+			name:     "missing state",
+			resource: &controllerPB.Resource{ResourcePermalink: modelPermalink},
+			field:    "resource.state",
+		},
+		{
+			name: "model",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: modelPermalink,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ONLINE},
+			},
+			workflowId: &workflowId,
+		},
+		{
+			name: "pipeline",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: pipelinePermalink,
+				State:             &controllerPB.Resource_PipelineState{PipelineState: pipelinePB.Pipeline_STATE_ACTIVE},
+			},
+		},
+		{
+			name: "connector",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: connectorPermalink,
+				State:             &controllerPB.Resource_ConnectorState{ConnectorState: connectorPB.Connector_STATE_CONNECTED},
+			},
+		},
+		{
+			name: "service",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: servicePermalink,
+				State:             &controllerPB.Resource_BackendState{BackendState: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING},
+			},
+		},
+		{
+			name: "model state on a pipeline",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: pipelinePermalink,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ONLINE},
+			},
+			field: "resource.model_state",
+		},
+		{
+			name: "backend state on a connector",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: connectorPermalink,
+				State:             &controllerPB.Resource_BackendState{BackendState: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING},
+			},
+			field: "resource.backend_state",
+		},
+		{
+			name: "model state out of range",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: modelPermalink,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_State(42)},
+			},
+			field: "resource.model_state",
+		},
+		{
+			name: "pipeline state out of range",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: pipelinePermalink,
+				State:             &controllerPB.Resource_PipelineState{PipelineState: pipelinePB.Pipeline_State(-1)},
+			},
+			field: "resource.pipeline_state",
+		},
+		{
+			name: "connector state out of range",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: connectorPermalink,
+				State:             &controllerPB.Resource_ConnectorState{ConnectorState: connectorPB.Connector_State(42)},
+			},
+			field: "resource.connector_state",
+		},
+		{
+			name: "backend state out of range",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: servicePermalink,
+				State:             &controllerPB.Resource_BackendState{BackendState: healthcheckPB.HealthCheckResponse_ServingStatus(42)},
+			},
+			field: "resource.backend_state",
+		},
+		{
+			name: "workflow on a pipeline",
+			resource: &controllerPB.Resource{
+				ResourcePermalink: pipelinePermalink,
+				State:             &controllerPB.Resource_PipelineState{PipelineState: pipelinePB.Pipeline_STATE_ACTIVE},
+			},
+			workflowId: &workflowId,
+			field:      "workflow_id",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := handler.NewPrivateHandler(&fakeService{})
+
+			_, err := h.UpdateResource(context.Background(), &controllerPB.UpdateResourceRequest{
+				Resource:   tc.resource,
+				WorkflowId: tc.workflowId,
+			})
+
+			if tc.field == "" {
+				assert.NoError(t, err)
+			} else {
+				assertInvalidArgument(t, err, tc.field)
+			}
+		})
+	}
+}
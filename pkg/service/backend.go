@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/external"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/triton"
+	"github.com/instill-ai/controller/internal/util"
+	"github.com/instill-ai/controller/pkg/logger"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+	mgmtPB "github.com/instill-ai/protogen-go/vdp/mgmt/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+func (s *service) ProbeBackend(ctx context.Context, cancel context.CancelFunc) error {
+	defer cancel()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var wg sync.WaitGroup
+
+	backendServices := config.Config.BackendServices
+
+	wg.Add(len(backendServices))
+
+	for _, backendService := range backendServices {
+		go func(backendService config.BackendServiceConfig) {
+			defer wg.Done()
+
+			probeCtx := ctx
+			if backendService.Timeout > 0 {
+				var probeCancel context.CancelFunc
+				probeCtx, probeCancel = context.WithTimeout(ctx, backendService.Timeout*time.Second)
+				defer probeCancel()
+			}
+
+			status, err := s.probeBackendService(probeCtx, backendService)
+
+			// the probe outcome trips or closes the breaker of the backend clients
+			if b, ok := s.breakers[backendService.Name]; ok {
+				if err != nil || status != healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING {
+					b.Failure()
+				} else {
+					b.Success()
+				}
+			}
+
+			reachable := newCondition(ConditionBackendReachable, err, "ProbeFailed")
+			if err != nil {
+				logger.Warn(fmt.Sprintf("[Controller] probe %s failed: %v", backendService.Name, err))
+				status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING
+			}
+
+			resourcePermalink := util.ConvertServiceToResourceName(backendService.Name)
+
+			if err := s.UpdateResourceState(ctx, &controllerPB.Resource{
+				ResourcePermalink: resourcePermalink,
+				State: &controllerPB.Resource_BackendState{
+					BackendState: status,
+				},
+			}); err != nil {
+				logger.Error(err.Error())
+				return
+			}
+
+			if err := s.UpdateResourceConditions(ctx, resourcePermalink, []Condition{reachable}); err != nil {
+				logger.Error(err.Error())
+			}
+
+			resp, _ := s.GetResourceState(ctx, resourcePermalink)
+
+			logger.Info(fmt.Sprintf("[Controller] Got %v", resp))
+		}(backendService)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (s *service) probeBackendService(ctx context.Context, backendService config.BackendServiceConfig) (healthcheckPB.HealthCheckResponse_ServingStatus, error) {
+	switch backendService.Kind {
+	case config.ProbeKindTriton:
+		return s.probeTriton(ctx)
+	case config.ProbeKindLiveness:
+		return s.probeLiveness(ctx, backendService.Name)
+	case config.ProbeKindGRPCHealth:
+		return s.probeGRPCHealth(ctx, backendService)
+	case config.ProbeKindHTTP:
+		return probeHTTP(ctx, backendService)
+	case config.ProbeKindTCP:
+		return probeTCP(ctx, backendService)
+	default:
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED, fmt.Errorf("probe kind %s not implemented", backendService.Kind)
+	}
+}
+
+func (s *service) probeTriton(ctx context.Context) (healthcheckPB.HealthCheckResponse_ServingStatus, error) {
+	if s.tritonClient == nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, newClientUnavailableError(util.SERVICE_TRITON_SERVER)
+	}
+
+	resp, err := s.tritonClient.ServerLive(ctx, &inferenceserver.ServerLiveRequest{})
+	if err != nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+	}
+
+	if !resp.GetLive() {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, nil
+	}
+
+	return healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, nil
+}
+
+func (s *service) probeLiveness(ctx context.Context, name string) (healthcheckPB.HealthCheckResponse_ServingStatus, error) {
+	var healthcheck *healthcheckPB.HealthCheckResponse
+
+	switch name {
+	case util.SERVICE_MODEL_BACKEND:
+		if s.modelPublicClient == nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, newClientUnavailableError(name)
+		}
+		resp, err := s.modelPublicClient.Liveness(ctx, &modelPB.LivenessRequest{})
+		if err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+		}
+		healthcheck = resp.GetHealthCheckResponse()
+	case util.SERVICE_PIPELINE_BACKEND:
+		if s.pipelinePublicClient == nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, newClientUnavailableError(name)
+		}
+		resp, err := s.pipelinePublicClient.Liveness(ctx, &pipelinePB.LivenessRequest{})
+		if err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+		}
+		healthcheck = resp.GetHealthCheckResponse()
+	case util.SERVICE_CONNECTOR_BACKEND:
+		if s.connectorPublicClient == nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, newClientUnavailableError(name)
+		}
+		resp, err := s.connectorPublicClient.Liveness(ctx, &connectorPB.LivenessRequest{})
+		if err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+		}
+		healthcheck = resp.GetHealthCheckResponse()
+	case util.SERVICE_MGMT_BACKEND:
+		if s.mgmtPublicClient == nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, newClientUnavailableError(name)
+		}
+		resp, err := s.mgmtPublicClient.Liveness(ctx, &mgmtPB.LivenessRequest{})
+		if err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+		}
+		healthcheck = resp.GetHealthCheckResponse()
+	default:
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED, fmt.Errorf("liveness probe not supported for service %s", name)
+	}
+
+	return healthcheck.GetStatus(), nil
+}
+
+// healthConn returns the connection the grpc.health.v1 probes of a backend
+// service reuse, dialed on its first probe
+func (s *service) healthConn(ctx context.Context, backendService config.BackendServiceConfig) (*grpc.ClientConn, error) {
+	s.healthConnsMu.Lock()
+	defer s.healthConnsMu.Unlock()
+
+	if clientConn, ok := s.healthConns[backendService.Name]; ok {
+		return clientConn, nil
+	}
+
+	clientConn, err := s.clients.Dial(ctx, backendService.Name, backendService.Address, backendService.TLS)
+	if err != nil {
+		return nil, err
+	}
+	s.healthConns[backendService.Name] = clientConn
+
+	return clientConn, nil
+}
+
+func (s *service) probeGRPCHealth(ctx context.Context, backendService config.BackendServiceConfig) (healthcheckPB.HealthCheckResponse_ServingStatus, error) {
+	if s.clients == nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, newClientUnavailableError(backendService.Name)
+	}
+
+	clientConn, err := s.healthConn(ctx, backendService)
+	if err != nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED, err
+	}
+
+	resp, err := healthgrpc.NewHealthClient(clientConn).Check(ctx, &healthgrpc.HealthCheckRequest{
+		Service: backendService.Service,
+	})
+	if err != nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+	}
+
+	if resp.GetStatus() != healthgrpc.HealthCheckResponse_SERVING {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, nil
+	}
+
+	return healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, nil
+}
+
+func probeHTTP(ctx context.Context, backendService config.BackendServiceConfig) (healthcheckPB.HealthCheckResponse_ServingStatus, error) {
+	transport := &http.Transport{}
+	if backendService.TLS.Enabled {
+		tlsConfig, err := external.NewClientTLSConfig(backendService.TLS)
+		if err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	client := &http.Client{Transport: transport}
+	defer client.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backendService.Address, nil)
+	if err != nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, nil
+	}
+
+	return healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, nil
+}
+
+func probeTCP(ctx context.Context, backendService config.BackendServiceConfig) (healthcheckPB.HealthCheckResponse_ServingStatus, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", backendService.Address)
+	if err != nil {
+		return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+	}
+	defer conn.Close()
+
+	if backendService.TLS.Enabled {
+		tlsConfig, err := external.NewClientTLSConfig(backendService.TLS)
+		if err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED, err
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName, _, _ = net.SplitHostPort(backendService.Address)
+		}
+		if err := tls.Client(conn, tlsConfig).HandshakeContext(ctx); err != nil {
+			return healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, err
+		}
+	}
+
+	return healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+// stateCache caches the stored values of resource state keys in front of etcd,
+// each with the etcd revision it was written or deleted at, so that a value
+// read before a newer write or deletion cannot replace it
+type stateCache interface {
+	// Get returns the cached value of a key, and false on a miss
+	Get(ctx context.Context, key string) (string, bool)
+	// Set caches the value of a key read with its mod revision, unless a
+	// newer revision of the key is cached
+	Set(ctx context.Context, key string, value string, modRevision int64)
+	// Delete invalidates a key written or deleted at a revision
+	Delete(ctx context.Context, key string, revision int64)
+}
+
+type noopStateCache struct{}
+
+func (noopStateCache) Get(ctx context.Context, key string) (string, bool) {
+	return "", false
+}
+
+func (noopStateCache) Set(ctx context.Context, key string, value string, modRevision int64) {}
+
+func (noopStateCache) Delete(ctx context.Context, key string, revision int64) {}
+
+// redisStateCache is a read-through cache shared by the controller replicas,
+// its entries expire after a TTL bounding the staleness left by missed watch
+// events. An entry is a hash of the value and its revision, an invalidation
+// leaving a tombstone at its revision until the TTL.
+//
+// The cache is only read while the snapshot of the replica is not ready, at
+// startup or while its watch is re-established, the snapshot serving every
+// other read. Writes still invalidate it on every replica, for the fallback
+// reads of the others not to be served stale values
+type redisStateCache struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+	requests  metric.Int64Counter
+}
+
+// redisStateCacheKeyPrefix prefixes the cache keys, followed by the etcd
+// namespace for the installations sharing a Redis to keep apart
+const redisStateCacheKeyPrefix = "controller:"
+
+// storeScript writes the value, revision and deletion flag of an entry unless
+// its current revision is newer, and sets its TTL if any
+var storeScript = redis.NewScript(`
+local current = redis.call("HGET", KEYS[1], "revision")
+if current and tonumber(current) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call("HSET", KEYS[1], "value", ARGV[1], "revision", ARGV[2], "deleted", ARGV[3])
+if tonumber(ARGV[4]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[4])
+end
+return 1
+`)
+
+func newStateCache(client *redis.Client) stateCache {
+	if !config.Config.Cache.Redis.Enabled || client == nil {
+		return noopStateCache{}
+	}
+
+	requests, err := otel.Meter("controller.service.meter").Int64Counter(
+		"controller.cache.requests",
+		metric.WithDescription("Number of resource state cache lookups by result"),
+	)
+	if err != nil {
+		requests, _ = noop.NewMeterProvider().Meter("").Int64Counter("")
+	}
+
+	return &redisStateCache{
+		client:    client,
+		keyPrefix: redisStateCacheKeyPrefix + config.Config.Etcd.Namespace,
+		ttl:       config.Config.Cache.Redis.TTL * time.Second,
+		requests:  requests,
+	}
+}
+
+func (c *redisStateCache) Get(ctx context.Context, key string) (string, bool) {
+	fields, err := c.client.HMGet(ctx, c.keyPrefix+key, "value", "deleted").Result()
+	if err != nil {
+		logger, _ := logger.GetZapLogger(ctx)
+		logger.Warn(fmt.Sprintf("[Controller] cache lookup of %s failed: %v", key, err))
+	}
+
+	// a missing entry has no fields, an invalidated one is deleted
+	value, ok := "", false
+	if len(fields) == 2 && fields[1] == "0" {
+		value, ok = fields[0].(string)
+	}
+
+	if !ok {
+		c.requests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "miss")))
+		return "", false
+	}
+
+	c.requests.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "hit")))
+	return value, true
+}
+
+func (c *redisStateCache) Set(ctx context.Context, key string, value string, modRevision int64) {
+	if err := c.store(ctx, key, value, modRevision, false); err != nil {
+		logger, _ := logger.GetZapLogger(ctx)
+		logger.Warn(fmt.Sprintf("[Controller] cache update of %s failed: %v", key, err))
+	}
+}
+
+func (c *redisStateCache) Delete(ctx context.Context, key string, revision int64) {
+	var err error
+	if revision > 0 {
+		err = c.store(ctx, key, "", revision, true)
+	} else {
+		// without a revision, nothing tells a stale value from a fresh one
+		err = c.client.Del(ctx, c.keyPrefix+key).Err()
+	}
+	if err != nil {
+		logger, _ := logger.GetZapLogger(ctx)
+		logger.Warn(fmt.Sprintf("[Controller] cache invalidation of %s failed: %v", key, err))
+	}
+}
+
+func (c *redisStateCache) store(ctx context.Context, key string, value string, revision int64, deleted bool) error {
+	deletedFlag := "0"
+	if deleted {
+		deletedFlag = "1"
+	}
+	return storeScript.Run(ctx, c.client, []string{c.keyPrefix + key}, value, revision, deletedFlag, c.ttl.Milliseconds()).Err()
+}
+
+// newRedisClient returns a client of the configured Redis if the cache or the
+// event publisher uses it, and nil otherwise
+func newRedisClient() *redis.Client {
+	if !config.Config.Cache.Redis.Enabled && config.Config.Notifier.Publisher.Kind != config.PublisherKindRedisStreams {
+		return nil
+	}
+	return redis.NewClient(&config.Config.Cache.Redis.RedisOptions)
+}
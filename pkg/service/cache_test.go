@@ -0,0 +1,262 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/instill-ai/controller/config"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/pkg/service"
+
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+)
+
+// useRedisCache enables the state cache on a miniredis server for the services
+// created by a test
+func useRedisCache(t *testing.T) *miniredis.Miniredis {
+	mr := miniredis.RunT(t)
+
+	cacheConfig := config.Config.Cache
+	t.Cleanup(func() {
+		config.Config.Cache = cacheConfig
+	})
+
+	config.Config.Cache.Redis.Enabled = true
+	config.Config.Cache.Redis.TTL = 60
+	config.Config.Cache.Redis.RedisOptions = redis.Options{Addr: mr.Addr()}
+
+	return mr
+}
+
+func TestStateCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newMockEtcdClient := func(ctrl *gomock.Controller, kv etcdv3.KV, watcher etcdv3.Watcher) etcdv3.Client {
+		return etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          kv,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     watcher,
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+	}
+
+	t.Run("hit after a miss", func(t *testing.T) {
+		useRedisCache(t)
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		// the second read is served from the cache
+		mockKV.
+			EXPECT().
+			Get(ctx, modelResourceName).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 3}},
+			}, nil).
+			Times(1)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV, NewMockWatcher(ctrl)), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		for i := 0; i < 2; i++ {
+			resource, err := s.GetResourceState(ctx, modelResourceName)
+			require.NoError(t, err)
+			assert.Equal(t, modelPB.Model_STATE_ONLINE, resource.GetModelState())
+		}
+	})
+
+	t.Run("invalidated on write", func(t *testing.T) {
+		useRedisCache(t)
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		gomock.InOrder(
+			mockKV.
+				EXPECT().
+				Get(ctx, modelResourceName).
+				Return(&etcdv3.GetResponse{
+					Kvs: []*mvccpb.KeyValue{{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 3}},
+				}, nil),
+			mockKV.
+				EXPECT().
+				Put(ctx, modelResourceName, "3").
+				Return(&etcdv3.PutResponse{Header: &etcdserverpb.ResponseHeader{Revision: 7}}, nil),
+			// the value written is cached again once read at its revision
+			mockKV.
+				EXPECT().
+				Get(ctx, modelResourceName).
+				Return(&etcdv3.GetResponse{
+					Kvs: []*mvccpb.KeyValue{{Key: []byte(modelResourceName), Value: []byte("3"), ModRevision: 7}},
+				}, nil),
+		)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV, NewMockWatcher(ctrl)), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := s.GetResourceState(ctx, modelResourceName)
+		require.NoError(t, err)
+
+		require.NoError(t, s.UpdateResourceState(ctx, &controllerPB.Resource{
+			ResourcePermalink: modelResourceName,
+			State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ERROR},
+		}))
+
+		for i := 0; i < 2; i++ {
+			resource, err := s.GetResourceState(ctx, modelResourceName)
+			require.NoError(t, err)
+			assert.Equal(t, modelPB.Model_STATE_ERROR, resource.GetModelState())
+		}
+	})
+
+	t.Run("stale read not cached", func(t *testing.T) {
+		useRedisCache(t)
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		mockKV.
+			EXPECT().
+			Put(ctx, modelResourceName, "3").
+			Return(&etcdv3.PutResponse{Header: &etcdserverpb.ResponseHeader{Revision: 10}}, nil).
+			Times(1)
+
+		// a read older than the write, e.g. from a lagging member, is never
+		// cached over its invalidation
+		mockKV.
+			EXPECT().
+			Get(ctx, modelResourceName).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 5}},
+			}, nil).
+			Times(2)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV, NewMockWatcher(ctrl)), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		require.NoError(t, s.UpdateResourceState(ctx, &controllerPB.Resource{
+			ResourcePermalink: modelResourceName,
+			State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ERROR},
+		}))
+
+		for i := 0; i < 2; i++ {
+			_, err := s.GetResourceState(ctx, modelResourceName)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("invalidated on watch event", func(t *testing.T) {
+		mr := useRedisCache(t)
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+		mockWatcher := NewMockWatcher(ctrl)
+
+		// the entries cached by another replica
+		mr.HSet("controller:"+modelResourceName, "value", "2", "revision", "3", "deleted", "0")
+		mr.HSet("controller:"+pipelineResourceName, "value", "1", "revision", "4", "deleted", "0")
+
+		mockKV.
+			EXPECT().
+			Get(ctx, "resources/", gomock.Any()).
+			Return(&etcdv3.GetResponse{
+				Header: &etcdserverpb.ResponseHeader{Revision: 5},
+			}, nil).
+			Times(1)
+
+		watchChan := make(chan etcdv3.WatchResponse)
+		mockWatcher.
+			EXPECT().
+			Watch(gomock.Any(), "resources/", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(etcdv3.WatchChan(watchChan)).
+			Times(1)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV, mockWatcher), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.WatchResources(ctx)
+		}()
+
+		watchChan <- etcdv3.WatchResponse{
+			Events: []*etcdv3.Event{
+				{
+					Type: mvccpb.PUT,
+					Kv:   &mvccpb.KeyValue{Key: []byte(modelResourceName), Value: []byte("3"), ModRevision: 6},
+				},
+				{
+					Type: mvccpb.DELETE,
+					Kv:   &mvccpb.KeyValue{Key: []byte(pipelineResourceName), ModRevision: 7},
+				},
+			},
+		}
+		// the previous response is applied once the next one is received
+		watchChan <- etcdv3.WatchResponse{}
+
+		assert.Equal(t, "1", mr.HGet("controller:"+modelResourceName, "deleted"))
+		assert.Equal(t, "6", mr.HGet("controller:"+modelResourceName, "revision"))
+		assert.Equal(t, "1", mr.HGet("controller:"+pipelineResourceName, "deleted"))
+		assert.Equal(t, "7", mr.HGet("controller:"+pipelineResourceName, "revision"))
+
+		close(watchChan)
+		<-done
+	})
+
+	t.Run("keys under the etcd namespace", func(t *testing.T) {
+		mr := useRedisCache(t)
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		etcdConfig := config.Config.Etcd
+		t.Cleanup(func() {
+			config.Config.Etcd = etcdConfig
+		})
+		config.Config.Etcd.Namespace = "instill/"
+
+		mockKV.
+			EXPECT().
+			Get(ctx, modelResourceName).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 3}},
+			}, nil).
+			Times(1)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV, NewMockWatcher(ctrl)), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := s.GetResourceState(ctx, modelResourceName)
+		require.NoError(t, err)
+
+		// the installations sharing the Redis do not read each other's entries
+		assert.Equal(t, "2", mr.HGet("controller:instill/"+modelResourceName, "value"))
+		assert.False(t, mr.Exists("controller:"+modelResourceName))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		// every read goes to etcd
+		mockKV.
+			EXPECT().
+			Get(ctx, modelResourceName).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 3}},
+			}, nil).
+			Times(2)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV, NewMockWatcher(ctrl)), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		for i := 0; i < 2; i++ {
+			resource, err := s.GetResourceState(ctx, modelResourceName)
+			require.NoError(t, err)
+			assert.Equal(t, modelPB.Model_STATE_ONLINE, resource.GetModelState())
+		}
+	})
+}
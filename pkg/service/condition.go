@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/util"
+)
+
+// conditionsUpdateAttempts bounds the attempts of a conditions update racing
+// with the updates of other replicas
+const conditionsUpdateAttempts = 5
+
+// ConditionType is the aspect of a resource a condition is about
+type ConditionType string
+
+const (
+	// ConditionBackendReachable tells whether the backend owning the resource
+	// answered the last probe
+	ConditionBackendReachable ConditionType = "BackendReachable"
+	// ConditionOperationInProgress tells whether a long-running operation is
+	// changing the resource
+	ConditionOperationInProgress ConditionType = "OperationInProgress"
+	// ConditionDependenciesReady tells whether the resources a pipeline is
+	// made of are all ready
+	ConditionDependenciesReady ConditionType = "DependenciesReady"
+	// ConditionTritonLoaded tells whether a model is loaded in Triton
+	ConditionTritonLoaded ConditionType = "TritonLoaded"
+)
+
+// ConditionStatus is the status of a condition
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition describes one aspect of the state of a resource
+type Condition struct {
+	Type               ConditionType   `json:"type"`
+	Status             ConditionStatus `json:"status"`
+	Reason             string          `json:"reason,omitempty"`
+	Message            string          `json:"message,omitempty"`
+	LastTransitionTime time.Time       `json:"lastTransitionTime"`
+}
+
+// ConditionReporter is implemented by the probers reporting conditions
+// specific to their resource type, observed is nil while the resource is
+// left as is
+type ConditionReporter[T any] interface {
+	Conditions(resource T, observed []*controllerPB.Resource) []Condition
+}
+
+func (s *service) GetResourceConditions(ctx context.Context, resourcePermalink string) ([]Condition, error) {
+	value, found, err := s.getValue(ctx, util.ConvertResourcePermalinkToConditionsName(resourcePermalink))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return []Condition{}, nil
+	}
+
+	conditions := []Condition{}
+	if err := json.Unmarshal(value, &conditions); err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid conditions of %s in etcd storage: %v", resourcePermalink, err)
+	}
+
+	return conditions, nil
+}
+
+// UpdateResourceConditions merges the given conditions into the stored ones,
+// the transition time of a condition only changes with its status. The merge
+// is written only if the stored conditions are unchanged since read, and
+// retried otherwise, so that the updates of other replicas are not lost
+func (s *service) UpdateResourceConditions(ctx context.Context, resourcePermalink string, conditions []Condition) error {
+	key := util.ConvertResourcePermalinkToConditionsName(resourcePermalink)
+
+	for attempt := 0; attempt < conditionsUpdateAttempts; attempt++ {
+		// read from etcd rather than the snapshot, for the revision compared
+		resp, err := s.etcdClient.Get(ctx, key)
+		if err != nil {
+			return storageError(err)
+		}
+
+		stored := []Condition{}
+		var modRevision int64
+		if len(resp.Kvs) > 0 {
+			modRevision = resp.Kvs[0].ModRevision
+			if err := json.Unmarshal(resp.Kvs[0].Value, &stored); err != nil {
+				return status.Errorf(codes.Internal, "invalid conditions of %s in etcd storage: %v", resourcePermalink, err)
+			}
+		}
+
+		value, err := json.Marshal(mergeConditions(stored, conditions, time.Now().UTC()))
+		if err != nil {
+			return err
+		}
+
+		// the mod revision of an absent key is 0
+		txnResp, err := s.etcdClient.Txn(ctx).
+			If(etcdv3.Compare(etcdv3.ModRevision(key), "=", modRevision)).
+			Then(etcdv3.OpPut(key, string(value))).
+			Commit()
+		if err != nil {
+			return storageError(err)
+		}
+
+		if txnResp.Succeeded {
+			if txnResp.Header != nil {
+				s.snapshot.put(key, value, txnResp.Header.Revision)
+			}
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.Aborted, "conditions of %s changed concurrently %d times", resourcePermalink, conditionsUpdateAttempts)
+}
+
+// mergeConditions returns the stored conditions updated with the given ones,
+// sorted by type
+func mergeConditions(stored []Condition, conditions []Condition, now time.Time) []Condition {
+	merged := map[ConditionType]Condition{}
+	for _, condition := range stored {
+		merged[condition.Type] = condition
+	}
+
+	for _, condition := range conditions {
+		condition.LastTransitionTime = now
+		if previous, ok := merged[condition.Type]; ok && previous.Status == condition.Status {
+			condition.LastTransitionTime = previous.LastTransitionTime
+		}
+		merged[condition.Type] = condition
+	}
+
+	result := make([]Condition, 0, len(merged))
+	for _, condition := range merged {
+		result = append(result, condition)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+func (s *service) DeleteResourceConditions(ctx context.Context, resourcePermalink string) error {
+	_, err := s.deleteValue(ctx, util.ConvertResourcePermalinkToConditionsName(resourcePermalink))
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// newCondition returns a condition of the given type, true if the given error
+// is nil and false with the error as message otherwise
+func newCondition(conditionType ConditionType, err error, reason string) Condition {
+	if err != nil {
+		return Condition{
+			Type:    conditionType,
+			Status:  ConditionFalse,
+			Reason:  reason,
+			Message: err.Error(),
+		}
+	}
+	return Condition{
+		Type:   conditionType,
+		Status: ConditionTrue,
+	}
+}
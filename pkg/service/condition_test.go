@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/instill-ai/controller/pkg/service"
+)
+
+// expectConditionsUpdate expects the conditions stored under a key to be
+// written if still at the given mod revision, and returns the value written
+func expectConditionsUpdate(ctrl *gomock.Controller, mockKV *MockKV, key string, modRevision int64) *string {
+	return expectConditionsTxn(ctrl, mockKV, key, modRevision, true)
+}
+
+// expectConditionsTxn expects a conditions transaction comparing the mod
+// revision of a key, and returns the value it writes
+func expectConditionsTxn(ctrl *gomock.Controller, mockKV *MockKV, key string, modRevision int64, succeeded bool) *string {
+	var written string
+
+	mockTxn := NewMockTxn(ctrl)
+	mockKV.
+		EXPECT().
+		Txn(gomock.Any()).
+		Return(mockTxn).
+		Times(1)
+	mockTxn.
+		EXPECT().
+		If(etcdv3.Compare(etcdv3.ModRevision(key), "=", modRevision)).
+		Return(mockTxn).
+		Times(1)
+	mockTxn.
+		EXPECT().
+		Then(gomock.Any()).
+		DoAndReturn(func(ops ...etcdv3.Op) etcdv3.Txn {
+			if len(ops) == 1 && ops[0].IsPut() && string(ops[0].KeyBytes()) == key {
+				written = string(ops[0].ValueBytes())
+			}
+			return mockTxn
+		}).
+		Times(1)
+	mockTxn.
+		EXPECT().
+		Commit().
+		Return(&etcdv3.TxnResponse{
+			Header:    &etcdserverpb.ResponseHeader{Revision: modRevision + 1},
+			Succeeded: succeeded,
+		}, nil).
+		Times(1)
+
+	return &written
+}
+
+func TestUpdateResourceConditions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conditionsKey := modelResourceName + "/conditions"
+	transitionTime := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	storedConditions := func(t *testing.T, conditions ...service.Condition) []byte {
+		value, err := json.Marshal(conditions)
+		require.NoError(t, err)
+		return value
+	}
+
+	newMockEtcdClient := func(ctrl *gomock.Controller, kv etcdv3.KV) etcdv3.Client {
+		return etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          kv,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+	}
+
+	t.Run("created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		mockKV.
+			EXPECT().
+			Get(ctx, conditionsKey).
+			Return(&etcdv3.GetResponse{}, nil).
+			Times(1)
+		// an absent key has the mod revision 0
+		written := expectConditionsUpdate(ctrl, mockKV, conditionsKey, 0)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		require.NoError(t, s.UpdateResourceConditions(ctx, modelResourceName, []service.Condition{
+			{Type: service.ConditionBackendReachable, Status: service.ConditionTrue},
+		}))
+
+		var conditions []service.Condition
+		require.NoError(t, json.Unmarshal([]byte(*written), &conditions))
+		require.Len(t, conditions, 1)
+		assert.Equal(t, service.ConditionTrue, conditions[0].Status)
+	})
+
+	t.Run("retried on conflict", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		// another replica updates the conditions between the read and the
+		// write, so the merge is done again on its update
+		gomock.InOrder(
+			mockKV.
+				EXPECT().
+				Get(ctx, conditionsKey).
+				Return(&etcdv3.GetResponse{
+					Kvs: []*mvccpb.KeyValue{{
+						Key:         []byte(conditionsKey),
+						Value:       storedConditions(t),
+						ModRevision: 3,
+					}},
+				}, nil),
+			mockKV.
+				EXPECT().
+				Get(ctx, conditionsKey).
+				Return(&etcdv3.GetResponse{
+					Kvs: []*mvccpb.KeyValue{{
+						Key: []byte(conditionsKey),
+						Value: storedConditions(t, service.Condition{
+							Type:               service.ConditionTritonLoaded,
+							Status:             service.ConditionFalse,
+							LastTransitionTime: transitionTime,
+						}),
+						ModRevision: 5,
+					}},
+				}, nil),
+		)
+		expectConditionsTxn(ctrl, mockKV, conditionsKey, 3, false)
+		written := expectConditionsUpdate(ctrl, mockKV, conditionsKey, 5)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		require.NoError(t, s.UpdateResourceConditions(ctx, modelResourceName, []service.Condition{
+			{Type: service.ConditionBackendReachable, Status: service.ConditionTrue},
+		}))
+
+		var conditions []service.Condition
+		require.NoError(t, json.Unmarshal([]byte(*written), &conditions))
+		require.Len(t, conditions, 2)
+		assert.Equal(t, service.ConditionBackendReachable, conditions[0].Type)
+		assert.Equal(t, service.ConditionTritonLoaded, conditions[1].Type)
+		assert.Equal(t, transitionTime, conditions[1].LastTransitionTime)
+	})
+
+	t.Run("aborted after repeated conflicts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		mockKV.
+			EXPECT().
+			Get(ctx, conditionsKey).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{
+					Key:         []byte(conditionsKey),
+					Value:       storedConditions(t),
+					ModRevision: 3,
+				}},
+			}, nil).
+			Times(5)
+		for i := 0; i < 5; i++ {
+			expectConditionsTxn(ctrl, mockKV, conditionsKey, 3, false)
+		}
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		err := s.UpdateResourceConditions(ctx, modelResourceName, []service.Condition{
+			{Type: service.ConditionBackendReachable, Status: service.ConditionTrue},
+		})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("transition time kept with the status", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockKV := NewMockKV(ctrl)
+
+		mockKV.
+			EXPECT().
+			Get(ctx, conditionsKey).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{{
+					Key: []byte(conditionsKey),
+					Value: storedConditions(t,
+						service.Condition{Type: service.ConditionBackendReachable, Status: service.ConditionTrue, LastTransitionTime: transitionTime},
+						service.Condition{Type: service.ConditionTritonLoaded, Status: service.ConditionTrue, LastTransitionTime: transitionTime},
+					),
+					ModRevision: 3,
+				}},
+			}, nil).
+			Times(1)
+		written := expectConditionsUpdate(ctrl, mockKV, conditionsKey, 3)
+
+		s := service.NewService(newMockEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		require.NoError(t, s.UpdateResourceConditions(ctx, modelResourceName, []service.Condition{
+			{Type: service.ConditionBackendReachable, Status: service.ConditionTrue},
+			{Type: service.ConditionTritonLoaded, Status: service.ConditionFalse, Reason: "Unloaded"},
+		}))
+
+		var conditions []service.Condition
+		require.NoError(t, json.Unmarshal([]byte(*written), &conditions))
+		require.Len(t, conditions, 2)
+		assert.Equal(t, transitionTime, conditions[0].LastTransitionTime)
+		assert.True(t, conditions[1].LastTransitionTime.After(transitionTime))
+	})
+}
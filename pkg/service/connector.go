@@ -3,174 +3,148 @@ package service
 import (
 	"context"
 	"fmt"
-	"sync"
 
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
 	"github.com/instill-ai/controller/internal/util"
-	"github.com/instill-ai/controller/pkg/logger"
 
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 )
 
 func (s *service) ProbeSourceConnectors(ctx context.Context, cancel context.CancelFunc) error {
 	defer cancel()
 
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var wg sync.WaitGroup
-
-	resp, err := s.connectorPrivateClient.ListSourceConnectorsAdmin(ctx, &connectorPB.ListSourceConnectorsAdminRequest{})
-
-	if err != nil {
-		return err
-	}
-
-	connectors := resp.SourceConnectors
-	nextPageToken := &resp.NextPageToken
-	totalSize := resp.TotalSize
-
-	for totalSize > util.DefaultPageSize {
-		resp, err := s.connectorPrivateClient.ListSourceConnectorsAdmin(ctx, &connectorPB.ListSourceConnectorsAdminRequest{
-			PageToken: nextPageToken,
-		})
-
-		if err != nil {
-			return err
-		}
-
-		nextPageToken = &resp.NextPageToken
-		totalSize -= util.DefaultPageSize
-		connectors = append(connectors, resp.SourceConnectors...)
-	}
-
-	connectorType := "source-connectors"
-
-	wg.Add(len(connectors))
-
-	for _, connector := range connectors {
-
-		go func(connector *connectorPB.SourceConnector) {
-			defer wg.Done()
-
-			resourcePermalink := util.ConvertUIDToResourcePermalink(connector.Uid, connectorType)
-
-			// if user desires disconnected
-			if connector.Connector.State == connectorPB.Connector_STATE_DISCONNECTED {
-				if err := s.UpdateResourceState(ctx, &controllerPB.Resource{
-					ResourcePermalink: resourcePermalink,
-					State: &controllerPB.Resource_ConnectorState{
-						ConnectorState: connectorPB.Connector_STATE_DISCONNECTED,
-					},
-				}); err != nil {
-					logger.Error(err.Error())
-					return
-				}
+	return runProber[*connectorPB.SourceConnector](ctx, s, &connectorProber[*connectorPB.SourceConnector]{
+		s:            s,
+		resourceType: util.RESOURCE_TYPE_SOURCE_CONNECTOR,
+		list: func(ctx context.Context, pageSize int64, pageToken string) ([]*connectorPB.SourceConnector, string, error) {
+			if s.connectorPrivateClient == nil {
+				return nil, "", newClientUnavailableError(util.SERVICE_CONNECTOR_BACKEND)
 			}
-			// if user desires connected
-			resp, err := s.connectorPrivateClient.CheckSourceConnector(ctx, &connectorPB.CheckSourceConnectorRequest{
-				SourceConnectorPermalink: fmt.Sprintf("%s/%s", connectorType, connector.Uid),
+			resp, err := s.connectorPrivateClient.ListSourceConnectorsAdmin(ctx, &connectorPB.ListSourceConnectorsAdminRequest{
+				PageSize:  &pageSize,
+				PageToken: util.PageTokenOrNil(pageToken),
 			})
 			if err != nil {
-				logger.Error(err.Error())
-				return
+				return nil, "", err
 			}
-			if err := s.UpdateResourceState(ctx, &controllerPB.Resource{
-				ResourcePermalink: resourcePermalink,
-				State: &controllerPB.Resource_ConnectorState{
-					ConnectorState: resp.State,
-				},
-			}); err != nil {
-				logger.Error(err.Error())
-				return
+			return resp.SourceConnectors, resp.NextPageToken, nil
+		},
+		check: func(ctx context.Context, permalink string) (connectorPB.Connector_State, error) {
+			if s.connectorPrivateClient == nil {
+				return connectorPB.Connector_STATE_UNSPECIFIED, newClientUnavailableError(util.SERVICE_CONNECTOR_BACKEND)
 			}
-
-			logResp, _ := s.GetResourceState(ctx, resourcePermalink)
-			logger.Info(fmt.Sprintf("[Controller] Got %v", logResp))
-		}(connector)
-	}
-
-	wg.Wait()
-
-	return nil
+			resp, err := s.connectorPrivateClient.CheckSourceConnector(ctx, &connectorPB.CheckSourceConnectorRequest{
+				SourceConnectorPermalink: permalink,
+			})
+			if err != nil {
+				return connectorPB.Connector_STATE_UNSPECIFIED, err
+			}
+			return resp.State, nil
+		},
+	})
 }
 
 func (s *service) ProbeDestinationConnectors(ctx context.Context, cancel context.CancelFunc) error {
 	defer cancel()
 
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var wg sync.WaitGroup
-
-	resp, err := s.connectorPrivateClient.ListDestinationConnectorsAdmin(ctx, &connectorPB.ListDestinationConnectorsAdminRequest{})
-
-	if err != nil {
-		return err
-	}
-
-	connectors := resp.DestinationConnectors
-	nextPageToken := &resp.NextPageToken
-	totalSize := resp.TotalSize
+	return runProber[*connectorPB.DestinationConnector](ctx, s, &connectorProber[*connectorPB.DestinationConnector]{
+		s:            s,
+		resourceType: util.RESOURCE_TYPE_DESTINATION_CONNECTOR,
+		list: func(ctx context.Context, pageSize int64, pageToken string) ([]*connectorPB.DestinationConnector, string, error) {
+			if s.connectorPrivateClient == nil {
+				return nil, "", newClientUnavailableError(util.SERVICE_CONNECTOR_BACKEND)
+			}
+			resp, err := s.connectorPrivateClient.ListDestinationConnectorsAdmin(ctx, &connectorPB.ListDestinationConnectorsAdminRequest{
+				PageSize:  &pageSize,
+				PageToken: util.PageTokenOrNil(pageToken),
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.DestinationConnectors, resp.NextPageToken, nil
+		},
+		check: func(ctx context.Context, permalink string) (connectorPB.Connector_State, error) {
+			if s.connectorPrivateClient == nil {
+				return connectorPB.Connector_STATE_UNSPECIFIED, newClientUnavailableError(util.SERVICE_CONNECTOR_BACKEND)
+			}
+			resp, err := s.connectorPrivateClient.CheckDestinationConnector(ctx, &connectorPB.CheckDestinationConnectorRequest{
+				DestinationConnectorPermalink: permalink,
+			})
+			if err != nil {
+				return connectorPB.Connector_STATE_UNSPECIFIED, err
+			}
+			return resp.State, nil
+		},
+	})
+}
 
-	for totalSize > util.DefaultPageSize {
-		resp, err := s.connectorPrivateClient.ListDestinationConnectorsAdmin(ctx, &connectorPB.ListDestinationConnectorsAdminRequest{
-			PageToken: nextPageToken,
-		})
+// connector is implemented by both source and destination connectors
+type connector interface {
+	GetUid() string
+	GetConnector() *connectorPB.Connector
+}
 
-		if err != nil {
-			return err
-		}
+// connectorProber checks the state of the connectors of one type in
+// connector-backend, unless the user desires them disconnected
+type connectorProber[T connector] struct {
+	s            *service
+	resourceType string
+	list         func(ctx context.Context, pageSize int64, pageToken string) ([]T, string, error)
+	check        func(ctx context.Context, permalink string) (connectorPB.Connector_State, error)
+}
 
-		nextPageToken = &resp.NextPageToken
-		totalSize -= util.DefaultPageSize
-		connectors = append(connectors, resp.DestinationConnectors...)
-	}
+func (p *connectorProber[T]) ResourceType() string {
+	return p.resourceType
+}
 
-	connectorType := "destination-connectors"
+func (p *connectorProber[T]) Backend() string {
+	return util.SERVICE_CONNECTOR_BACKEND
+}
 
-	wg.Add(len(connectors))
+func (p *connectorProber[T]) List(ctx context.Context, pageSize int64, pageToken string) ([]T, string, error) {
+	return p.list(ctx, pageSize, pageToken)
+}
 
-	for _, connector := range connectors {
+func (p *connectorProber[T]) UID(connector T) string {
+	return connector.GetUid()
+}
 
-		go func(connector *connectorPB.DestinationConnector) {
-			defer wg.Done()
+func (p *connectorProber[T]) Version(ctx context.Context, connector T) string {
+	return resourceVersion(connector.GetConnector().GetUpdateTime(), connector.GetConnector().GetState())
+}
 
-			resourcePermalink := util.ConvertUIDToResourcePermalink(connector.Uid, connectorType)
+// Desired returns whether the user connected or disconnected a connector, a
+// disconnected connector is not checked since connector-backend would spawn a
+// container to do so
+func (p *connectorProber[T]) Desired(connector T) (*controllerPB.Resource, bool) {
+	desired := connectorPB.Connector_STATE_CONNECTED
+	if connector.GetConnector().GetState() == connectorPB.Connector_STATE_DISCONNECTED {
+		desired = connectorPB.Connector_STATE_DISCONNECTED
+	}
+	return &controllerPB.Resource{
+		State: &controllerPB.Resource_ConnectorState{
+			ConnectorState: desired,
+		},
+	}, desired == connectorPB.Connector_STATE_DISCONNECTED
+}
 
-			// if user desires disconnected
-			if connector.Connector.State == connectorPB.Connector_STATE_DISCONNECTED {
-				if err := s.UpdateResourceState(ctx, &controllerPB.Resource{
-					ResourcePermalink: resourcePermalink,
-					State: &controllerPB.Resource_ConnectorState{
-						ConnectorState: connectorPB.Connector_STATE_DISCONNECTED,
-					},
-				}); err != nil {
-					logger.Error(err.Error())
-					return
-				}
-			}
-			// if user desires connected
-			resp, err := s.connectorPrivateClient.CheckDestinationConnector(ctx, &connectorPB.CheckDestinationConnectorRequest{
-				DestinationConnectorPermalink: fmt.Sprintf("%s/%s", connectorType, connector.Uid),
-			})
-			if err != nil {
-				logger.Error(err.Error())
-				return
-			}
-			if err := s.UpdateResourceState(ctx, &controllerPB.Resource{
-				ResourcePermalink: resourcePermalink,
-				State: &controllerPB.Resource_ConnectorState{
-					ConnectorState: resp.State,
-				},
-			}); err != nil {
-				logger.Error(err.Error())
-				return
-			}
-			logResp, _ := s.GetResourceState(ctx, resourcePermalink)
-			logger.Info(fmt.Sprintf("[Controller] Got %v", logResp))
-		}(connector)
+func (p *connectorProber[T]) Check(ctx context.Context, connector T) ([]*controllerPB.Resource, error) {
+	state := connectorPB.Connector_STATE_UNSPECIFIED
+	if err := p.s.breakers[p.Backend()].Do(func() (err error) {
+		state, err = p.check(ctx, fmt.Sprintf("%s/%s", p.resourceType, connector.GetUid()))
+		return err
+	}); err != nil {
+		return nil, err
 	}
 
-	wg.Wait()
+	return []*controllerPB.Resource{{
+		ResourcePermalink: util.ConvertUIDToResourcePermalink(connector.GetUid(), p.resourceType),
+		State: &controllerPB.Resource_ConnectorState{
+			ConnectorState: state,
+		},
+	}}, nil
+}
 
-	return nil
+func (p *connectorProber[T]) Derive(connector T, observed []*controllerPB.Resource) *controllerPB.Resource {
+	return observed[0]
 }
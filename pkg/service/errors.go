@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/controller/internal/util"
+)
+
+// storageResourceType is the resource type reported in the details of the
+// errors raised by etcd
+const storageResourceType = "etcd"
+
+// newNotFoundError returns a NotFound error about a stored resource record
+func newNotFoundError(resourcePermalink string, description string) error {
+	resourceType := ""
+	if permalink, err := util.ParseResourcePermalink(resourcePermalink); err == nil {
+		resourceType = permalink.Type
+	}
+
+	st := status.New(codes.NotFound, description)
+	if detailed, err := st.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: resourcePermalink,
+		Description:  description,
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// newClientUnavailableError returns an Unavailable error about a backend
+// whose client could not be set up, so that the backend is reported as down
+// rather than called through a nil client
+func newClientUnavailableError(backend string) error {
+	description := fmt.Sprintf("%s client is not available", backend)
+
+	st := status.New(codes.Unavailable, description)
+	if detailed, err := st.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: util.RESOURCE_TYPE_SERVICE,
+		ResourceName: backend,
+		Description:  description,
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// storageError converts an etcd error to a status telling clients whether to
+// retry: Unavailable while etcd cannot be reached, Aborted when the request
+// conflicts with the stored revisions, and Internal otherwise
+func storageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var code codes.Code
+	var etcdErr rpctypes.EtcdError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.As(err, &etcdErr):
+		code = etcdErr.Code()
+	default:
+		code = status.Code(err)
+	}
+
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		code = codes.Unavailable
+	case codes.Aborted, codes.OutOfRange, codes.FailedPrecondition:
+		// e.g. a revision compacted or changed meanwhile
+		code = codes.Aborted
+	case codes.Canceled:
+	default:
+		code = codes.Internal
+	}
+
+	description := err.Error()
+
+	st := status.New(code, fmt.Sprintf("resource storage: %s", description))
+	if detailed, err := st.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: storageResourceType,
+		Description:  description,
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
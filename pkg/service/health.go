@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/pkg/logger"
+
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+)
+
+// startupPhaseStarting is the phase of a controller which has not started
+// waiting for its dependencies yet
+const startupPhaseStarting = "starting"
+
+const (
+	startupBackoffMin = 500 * time.Millisecond
+	startupBackoffMax = 5 * time.Second
+)
+
+// RecordProbeCycle records the completion time of a successful probe cycle
+func (s *service) RecordProbeCycle(t time.Time) {
+	s.lastProbeCycle.Store(t.UnixNano())
+}
+
+// SetPopulated records whether the stored resource states have been fully
+// populated since the controller started or last reconnected to etcd
+func (s *service) SetPopulated(populated bool) {
+	s.populated.Store(populated)
+	if !populated {
+		s.tracker.reset()
+	}
+}
+
+// CheckHealth returns an error describing why the controller is not healthy,
+// or nil if it is connected to etcd and its control loop is up to date
+func (s *service) CheckHealth(ctx context.Context) error {
+	if phase, _ := s.startupPhase.Load().(string); phase != "" {
+		return fmt.Errorf("controller is starting up: %s", phase)
+	}
+
+	conn := s.etcdClient.ActiveConnection()
+	if conn == nil || conn.GetState() != connectivity.Ready {
+		return fmt.Errorf("etcd connection is not ready")
+	}
+
+	if !s.populated.Load() {
+		return fmt.Errorf("resource states are not populated yet")
+	}
+
+	return s.checkProbeCycle()
+}
+
+// checkProbeCycle returns an error if the control loop has not completed a
+// probe cycle within the probe stale threshold
+func (s *service) checkProbeCycle() error {
+	threshold := config.Config.Server.ProbeStaleThreshold * time.Second
+	lastProbeCycle := s.lastProbeCycle.Load()
+	if lastProbeCycle == 0 {
+		return fmt.Errorf("control loop has not completed a probe cycle yet")
+	}
+	if elapsed := s.now().Sub(time.Unix(0, lastProbeCycle)); threshold > 0 && elapsed > threshold {
+		return fmt.Errorf("last successful probe cycle was %v ago, exceeding %v", elapsed.Round(time.Second), threshold)
+	}
+
+	return nil
+}
+
+// WaitForStartup waits until the controller can safely start probing, i.e.
+// its etcd connection is ready and the required backends are reachable, the
+// latter being retried with backoff until the startup timeout. The phase
+// waited on is reported by CheckHealth meanwhile
+func (s *service) WaitForStartup(ctx context.Context) error {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	s.startupPhase.Store("waiting for the etcd connection")
+	if err := s.waitForEtcd(ctx); err != nil {
+		return err
+	}
+
+	backendServices := make(map[string]config.BackendServiceConfig)
+	for _, backendService := range config.Config.BackendServices {
+		backendServices[backendService.Name] = backendService
+	}
+
+	startupCtx, cancel := context.WithTimeout(ctx, config.Config.Server.Startup.Timeout*time.Second)
+	defer cancel()
+
+	for _, name := range config.Config.Server.Startup.RequiredBackends {
+		backendService, ok := backendServices[name]
+		if !ok {
+			continue
+		}
+
+		s.startupPhase.Store(fmt.Sprintf("waiting for backend %s", name))
+		if err := s.waitForBackend(startupCtx, backendService); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the control loop reports the backend as down rather than the
+			// controller not starting at all
+			logger.Warn(fmt.Sprintf("[controller] backend %s is not reachable within the startup timeout, starting anyway: %v", name, err))
+			continue
+		}
+		logger.Info(fmt.Sprintf("[controller] backend %s is reachable", name))
+	}
+
+	s.startupPhase.Store("")
+
+	return nil
+}
+
+func (s *service) waitForEtcd(ctx context.Context) error {
+	for {
+		conn := s.etcdClient.ActiveConnection()
+		if conn == nil {
+			select {
+			case <-time.After(startupBackoffMin):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// waitForBackend probes a backend service until it is serving, backing off
+// between the attempts, and returns the last failure once the context is done
+func (s *service) waitForBackend(ctx context.Context, backendService config.BackendServiceConfig) error {
+	backoff := startupBackoffMin
+	for {
+		probeCtx, cancel := ctx, context.CancelFunc(func() {})
+		if backendService.Timeout > 0 {
+			probeCtx, cancel = context.WithTimeout(ctx, backendService.Timeout*time.Second)
+		}
+		status, err := s.probeBackendService(probeCtx, backendService)
+		cancel()
+
+		if err == nil && status == healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING {
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("backend service %s is %s", backendService.Name, status)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+
+		if backoff *= 2; backoff > startupBackoffMax {
+			backoff = startupBackoffMax
+		}
+	}
+}
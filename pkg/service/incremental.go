@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/util"
+)
+
+// probeTracker remembers what each resource looked like the last time it was
+// evaluated, so that probe cycles between full sweeps only re-evaluate the
+// resources that changed or whose dependencies changed
+type probeTracker struct {
+	mu sync.Mutex
+	// versions holds the version of each resource at its last evaluation
+	versions map[string]string
+	// states holds the last state written for each resource
+	states map[string]int
+	// components holds the component resource names of each pipeline
+	components map[string][]string
+	// lastSweep holds the completion time of the last full sweep per resource type
+	lastSweep map[string]time.Time
+	// now returns the current time
+	now func() time.Time
+}
+
+func newProbeTracker(now func() time.Time) *probeTracker {
+	t := &probeTracker{now: now}
+	t.reset()
+	return t
+}
+
+// reset forgets everything, forcing a full sweep of every resource type
+func (t *probeTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.versions = map[string]string{}
+	t.states = map[string]int{}
+	t.components = map[string][]string{}
+	t.lastSweep = map[string]time.Time{}
+}
+
+// sweepDue reports whether the next probe cycle of a resource type must
+// evaluate every resource regardless of its version
+func (t *probeTracker) sweepDue(resourceType string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last, ok := t.lastSweep[resourceType]
+	interval := config.Config.Server.FullSweepInterval * time.Second
+	return !ok || interval <= 0 || t.now().Sub(last) >= interval
+}
+
+// sweepDone records the completion of a full sweep of a resource type
+func (t *probeTracker) sweepDone(resourceType string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastSweep[resourceType] = t.now()
+}
+
+// unchanged reports whether a resource was already evaluated at the given
+// version, an empty version never being
+func (t *probeTracker) unchanged(resourcePermalink string, version string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.versions[resourcePermalink]
+	return ok && version != "" && v == version
+}
+
+// evaluated records the version a resource was successfully evaluated at
+func (t *probeTracker) evaluated(resourcePermalink string, version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.versions[resourcePermalink] = version
+}
+
+// invalidate forces the next probe cycle to re-evaluate a resource
+func (t *probeTracker) invalidate(resourcePermalink string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.versions, resourcePermalink)
+}
+
+func (t *probeTracker) setState(resourcePermalink string, state int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.states[resourcePermalink] = state
+}
+
+func (t *probeTracker) setComponents(resourcePermalink string, resourceNames []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.components[resourcePermalink] = resourceNames
+}
+
+func (t *probeTracker) getComponents(resourcePermalink string) ([]string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resourceNames, ok := t.components[resourcePermalink]
+	return resourceNames, ok
+}
+
+// forget drops everything known about a deleted resource
+func (t *probeTracker) forget(resourcePermalink string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.versions, resourcePermalink)
+	delete(t.states, resourcePermalink)
+	delete(t.components, resourcePermalink)
+}
+
+// dependencyVersion fingerprints the last known states of the resources
+// referenced by pipeline components, e.g. "models/{uid}"
+func (t *probeTracker) dependencyVersion(resourceNames []string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fingerprints := make([]string, 0, len(resourceNames))
+	for _, resourceName := range resourceNames {
+		state := "?"
+		if parts := strings.SplitN(resourceName, "/", 2); len(parts) == 2 {
+			if s, ok := t.states[util.ConvertUIDToResourcePermalink(parts[1], parts[0])]; ok {
+				state = fmt.Sprint(s)
+			}
+		}
+		fingerprints = append(fingerprints, fmt.Sprintf("%s=%s", resourceName, state))
+	}
+	sort.Strings(fingerprints)
+
+	return strings.Join(fingerprints, ",")
+}
+
+// resourceVersion identifies a revision of a resource from its update time
+// and the state reported by its backend
+func resourceVersion(updateTime *timestamppb.Timestamp, state fmt.Stringer) string {
+	return fmt.Sprintf("%d/%s", updateTime.AsTime().UnixNano(), state)
+}
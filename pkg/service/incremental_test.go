@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/instill-ai/controller/config"
+)
+
+// fakeClock is a clock only moving when advanced
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestProbeTrackerUnchanged(t *testing.T) {
+	const resourcePermalink = "resources/a5ec3a1a-5c2f-4b1e-9b2f-0d3c4e5f6a7b/types/models"
+
+	for _, tc := range []struct {
+		name string
+		// evaluated holds the versions the resource was evaluated at
+		evaluated []string
+		// invalidated invalidates the resource after its evaluations
+		invalidated bool
+		version     string
+		unchanged   bool
+	}{
+		{name: "never evaluated", version: "v1"},
+		{name: "same version", evaluated: []string{"v1"}, version: "v1", unchanged: true},
+		{name: "new version", evaluated: []string{"v1"}, version: "v2"},
+		{name: "evaluated again", evaluated: []string{"v1", "v2"}, version: "v2", unchanged: true},
+		{name: "invalidated", evaluated: []string{"v1"}, invalidated: true, version: "v1"},
+		{name: "empty version", evaluated: []string{""}, version: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := newProbeTracker(time.Now)
+
+			for _, version := range tc.evaluated {
+				tracker.evaluated(resourcePermalink, version)
+			}
+			if tc.invalidated {
+				tracker.invalidate(resourcePermalink)
+			}
+
+			assert.Equal(t, tc.unchanged, tracker.unchanged(resourcePermalink, tc.version))
+		})
+	}
+}
+
+func TestProbeTrackerSweepDue(t *testing.T) {
+	fullSweepInterval := config.Config.Server.FullSweepInterval
+	t.Cleanup(func() {
+		config.Config.Server.FullSweepInterval = fullSweepInterval
+	})
+
+	for _, tc := range []struct {
+		name string
+		// interval is the full sweep interval in seconds
+		interval time.Duration
+		swept    bool
+		elapsed  time.Duration
+		reset    bool
+		due      bool
+	}{
+		{name: "never swept", interval: 60, due: true},
+		{name: "swept within the interval", interval: 60, swept: true, elapsed: 59 * time.Second},
+		{name: "interval elapsed", interval: 60, swept: true, elapsed: 60 * time.Second, due: true},
+		{name: "sweeping every cycle", interval: 0, swept: true, due: true},
+		{name: "reset", interval: 60, swept: true, reset: true, due: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config.Config.Server.FullSweepInterval = tc.interval
+
+			clock := &fakeClock{t: time.Unix(1700000000, 0)}
+			tracker := newProbeTracker(clock.now)
+
+			if tc.swept {
+				tracker.sweepDone("models")
+			}
+			if tc.reset {
+				tracker.reset()
+			}
+			clock.advance(tc.elapsed)
+
+			assert.Equal(t, tc.due, tracker.sweepDue("models"))
+			// the sweep of a resource type does not affect the others
+			assert.True(t, tracker.sweepDue("pipelines"))
+		})
+	}
+}
+
+func TestCheckProbeCycle(t *testing.T) {
+	probeStaleThreshold := config.Config.Server.ProbeStaleThreshold
+	t.Cleanup(func() {
+		config.Config.Server.ProbeStaleThreshold = probeStaleThreshold
+	})
+
+	for _, tc := range []struct {
+		name string
+		// threshold is the probe stale threshold in seconds
+		threshold time.Duration
+		probed    bool
+		elapsed   time.Duration
+		healthy   bool
+	}{
+		{name: "no probe cycle", threshold: 30},
+		{name: "within the threshold", threshold: 30, probed: true, elapsed: 30 * time.Second, healthy: true},
+		{name: "stale", threshold: 30, probed: true, elapsed: 31 * time.Second},
+		{name: "threshold disabled", threshold: 0, probed: true, elapsed: time.Hour, healthy: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config.Config.Server.ProbeStaleThreshold = tc.threshold
+
+			clock := &fakeClock{t: time.Unix(1700000000, 0)}
+			s := &service{now: clock.now}
+
+			if tc.probed {
+				s.RecordProbeCycle(clock.now())
+			}
+			clock.advance(tc.elapsed)
+
+			if tc.healthy {
+				assert.NoError(t, s.checkProbeCycle())
+			} else {
+				assert.Error(t, s.checkProbeCycle())
+			}
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -99,13 +98,9 @@ func (m *MockKV) Get(arg0 context.Context, arg1 string, arg2 ...clientv3.OpOptio
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Get", varargs...)
-	ret0 := clientv3.GetResponse{
-		Kvs: []*mvccpb.KeyValue{
-			{Value: []byte{0}},
-		},
-	}
+	ret0, _ := ret[0].(*clientv3.GetResponse)
 	ret1, _ := ret[1].(error)
-	return &ret0, ret1
+	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
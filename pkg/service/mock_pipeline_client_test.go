@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha (interfaces: PipelinePrivateServiceClient)
+
+// Package service_test is a generated GoMock package.
+package service_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	pipelinev1alpha "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+	grpc "google.golang.org/grpc"
+)
+
+// MockPipelinePrivateServiceClient is a mock of PipelinePrivateServiceClient interface.
+type MockPipelinePrivateServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPipelinePrivateServiceClientMockRecorder
+}
+
+// MockPipelinePrivateServiceClientMockRecorder is the mock recorder for MockPipelinePrivateServiceClient.
+type MockPipelinePrivateServiceClientMockRecorder struct {
+	mock *MockPipelinePrivateServiceClient
+}
+
+// NewMockPipelinePrivateServiceClient creates a new mock instance.
+func NewMockPipelinePrivateServiceClient(ctrl *gomock.Controller) *MockPipelinePrivateServiceClient {
+	mock := &MockPipelinePrivateServiceClient{ctrl: ctrl}
+	mock.recorder = &MockPipelinePrivateServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPipelinePrivateServiceClient) EXPECT() *MockPipelinePrivateServiceClientMockRecorder {
+	return m.recorder
+}
+
+// ListPipelinesAdmin mocks base method.
+func (m *MockPipelinePrivateServiceClient) ListPipelinesAdmin(arg0 context.Context, arg1 *pipelinev1alpha.ListPipelinesAdminRequest, arg2 ...grpc.CallOption) (*pipelinev1alpha.ListPipelinesAdminResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPipelinesAdmin", varargs...)
+	ret0, _ := ret[0].(*pipelinev1alpha.ListPipelinesAdminResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPipelinesAdmin indicates an expected call of ListPipelinesAdmin.
+func (mr *MockPipelinePrivateServiceClientMockRecorder) ListPipelinesAdmin(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPipelinesAdmin", reflect.TypeOf((*MockPipelinePrivateServiceClient)(nil).ListPipelinesAdmin), varargs...)
+}
+
+// LookUpPipelineAdmin mocks base method.
+func (m *MockPipelinePrivateServiceClient) LookUpPipelineAdmin(arg0 context.Context, arg1 *pipelinev1alpha.LookUpPipelineAdminRequest, arg2 ...grpc.CallOption) (*pipelinev1alpha.LookUpPipelineAdminResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LookUpPipelineAdmin", varargs...)
+	ret0, _ := ret[0].(*pipelinev1alpha.LookUpPipelineAdminResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookUpPipelineAdmin indicates an expected call of LookUpPipelineAdmin.
+func (mr *MockPipelinePrivateServiceClientMockRecorder) LookUpPipelineAdmin(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookUpPipelineAdmin", reflect.TypeOf((*MockPipelinePrivateServiceClient)(nil).LookUpPipelineAdmin), varargs...)
+}
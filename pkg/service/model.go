@@ -3,97 +3,146 @@ package service
 import (
 	"context"
 	"fmt"
-	"sync"
 
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
 	"github.com/instill-ai/controller/internal/util"
-	"github.com/instill-ai/controller/pkg/logger"
 
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
 )
 
 func (s *service) ProbeModels(ctx context.Context, cancel context.CancelFunc) error {
 	defer cancel()
 
-	logger, _ := logger.GetZapLogger(ctx)
+	return runProber[*modelPB.Model](ctx, s, &modelProber{s: s})
+}
+
+// modelProber checks the state of models in model-backend, leaving the
+// models with an operation in progress to the operation
+type modelProber struct {
+	s *service
+}
 
-	var wg sync.WaitGroup
+func (p *modelProber) ResourceType() string {
+	return util.RESOURCE_TYPE_MODEL
+}
 
-	resp, err := s.modelPrivateClient.ListModelsAdmin(ctx, &modelPB.ListModelsAdminRequest{})
+func (p *modelProber) Backend() string {
+	return util.SERVICE_MODEL_BACKEND
+}
 
+func (p *modelProber) List(ctx context.Context, pageSize int64, pageToken string) ([]*modelPB.Model, string, error) {
+	if p.s.modelPrivateClient == nil {
+		return nil, "", newClientUnavailableError(p.Backend())
+	}
+	resp, err := p.s.modelPrivateClient.ListModelsAdmin(ctx, &modelPB.ListModelsAdminRequest{
+		PageSize:  &pageSize,
+		PageToken: util.PageTokenOrNil(pageToken),
+	})
 	if err != nil {
-		return err
+		return nil, "", err
 	}
+	return resp.Models, resp.NextPageToken, nil
+}
 
-	models := resp.Models
-	nextPageToken := &resp.NextPageToken
-	totalSize := resp.TotalSize
+func (p *modelProber) UID(model *modelPB.Model) string {
+	return model.Uid
+}
 
-	for totalSize > util.DefaultPageSize {
-		resp, err := s.modelPrivateClient.ListModelsAdmin(ctx, &modelPB.ListModelsAdminRequest{
-			PageToken: nextPageToken,
-		})
+func (p *modelProber) Version(ctx context.Context, model *modelPB.Model) string {
+	// the state keeps changing until the operation is done
+	if workflowId, _ := p.s.GetResourceWorkflowId(ctx, util.ConvertUIDToResourcePermalink(model.Uid, p.ResourceType())); workflowId != nil {
+		return ""
+	}
+	return resourceVersion(model.UpdateTime, model.State)
+}
+
+// Desired returns whether the user deployed or undeployed a model, both are
+// checked to tell an undeployed model from a failing one
+func (p *modelProber) Desired(model *modelPB.Model) (*controllerPB.Resource, bool) {
+	desired := modelPB.Model_STATE_ONLINE
+	if model.State == modelPB.Model_STATE_OFFLINE {
+		desired = modelPB.Model_STATE_OFFLINE
+	}
+	return &controllerPB.Resource{
+		State: &controllerPB.Resource_ModelState{
+			ModelState: desired,
+		},
+	}, false
+}
 
+func (p *modelProber) Check(ctx context.Context, model *modelPB.Model) ([]*controllerPB.Resource, error) {
+	resourcePermalink := util.ConvertUIDToResourcePermalink(model.Uid, p.ResourceType())
+
+	workflowId, _ := p.s.GetResourceWorkflowId(ctx, resourcePermalink)
+
+	if workflowId != nil {
+		opInfo, err := p.s.getOperationInfo(*workflowId, util.RESOURCE_TYPE_MODEL)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if opInfo.Done {
+			if err := p.s.DeleteResourceWorkflowId(ctx, resourcePermalink); err != nil {
+				return nil, err
+			}
 		}
+		return nil, nil
+	}
 
-		nextPageToken = &resp.NextPageToken
-		totalSize -= util.DefaultPageSize
-		models = append(models, resp.Models...)
+	var resp *modelPB.CheckModelResponse
+	if err := p.s.breakers[p.Backend()].Do(func() (err error) {
+		if p.s.modelPrivateClient == nil {
+			return newClientUnavailableError(p.Backend())
+		}
+		resp, err = p.s.modelPrivateClient.CheckModel(ctx, &modelPB.CheckModelRequest{
+			ModelPermalink: fmt.Sprintf("%s/%s", p.ResourceType(), model.Uid),
+		})
+		return err
+	}); err != nil {
+		return nil, err
 	}
 
-	resourceType := "models"
-
-	wg.Add(len(models))
-
-	for _, model := range models {
-
-		go func(model *modelPB.Model) {
-			defer wg.Done()
-
-			resourcePermalink := util.ConvertUIDToResourcePermalink(model.Uid, resourceType)
-
-			workflowId, _ := s.GetResourceWorkflowId(ctx, resourcePermalink)
-
-			if workflowId != nil {
-				opInfo, err := s.getOperationInfo(*workflowId, util.RESOURCE_TYPE_MODEL)
-				if err != nil {
-					logger.Error(err.Error())
-					return
-				}
-				if opInfo.Done {
-					if err := s.DeleteResourceWorkflowId(ctx, resourcePermalink); err != nil {
-						logger.Error(err.Error())
-						return
-					}
-				}
-			} else {
-				if resp, err := s.modelPrivateClient.CheckModel(ctx, &modelPB.CheckModelRequest{
-					ModelPermalink: fmt.Sprintf("%s/%s", resourceType, model.Uid),
-				}); err == nil {
-					if err = s.UpdateResourceState(ctx, &controllerPB.Resource{
-						ResourcePermalink: resourcePermalink,
-						State: &controllerPB.Resource_ModelState{
-							ModelState: resp.State,
-						},
-					}); err != nil {
-						logger.Error(err.Error())
-						return
-					}
-				} else {
-					logger.Error(err.Error())
-					return
-				}
-			}
+	return []*controllerPB.Resource{{
+		ResourcePermalink: resourcePermalink,
+		State: &controllerPB.Resource_ModelState{
+			ModelState: resp.State,
+		},
+	}}, nil
+}
 
-			logResp, _ := s.GetResourceState(ctx, resourcePermalink)
-			logger.Info(fmt.Sprintf("[Controller] Got %v", logResp))
-		}(model)
+func (p *modelProber) Derive(model *modelPB.Model, observed []*controllerPB.Resource) *controllerPB.Resource {
+	return observed[0]
+}
 
+func (p *modelProber) Conditions(model *modelPB.Model, observed []*controllerPB.Resource) []Condition {
+	if observed == nil {
+		return []Condition{{
+			Type:   ConditionOperationInProgress,
+			Status: ConditionTrue,
+			Reason: "OperationRunning",
+		}}
 	}
 
-	wg.Wait()
+	tritonLoaded := Condition{
+		Type:   ConditionTritonLoaded,
+		Status: ConditionFalse,
+		Reason: "ModelNotOnline",
+	}
+	switch state := observed[0].GetModelState(); state {
+	case modelPB.Model_STATE_ONLINE:
+		tritonLoaded.Status = ConditionTrue
+		tritonLoaded.Reason = ""
+	case modelPB.Model_STATE_UNSPECIFIED:
+		tritonLoaded.Status = ConditionUnknown
+		tritonLoaded.Reason = "StateUnspecified"
+	default:
+		tritonLoaded.Message = fmt.Sprintf("model-backend reports the model as %s", state)
+	}
 
-	return nil
+	return []Condition{
+		{
+			Type:   ConditionOperationInProgress,
+			Status: ConditionFalse,
+		},
+		tritonLoaded,
+	}
 }
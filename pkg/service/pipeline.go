@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/controller/internal/breaker"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
 	"github.com/instill-ai/controller/internal/util"
 	"github.com/instill-ai/controller/pkg/logger"
 
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
 	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
 )
@@ -18,171 +21,230 @@ import (
 func (s *service) ProbePipelines(ctx context.Context, cancel context.CancelFunc) error {
 	defer cancel()
 
-	logger, _ := logger.GetZapLogger(ctx)
+	// recipes are only listed on full sweeps, and looked up for the pipelines
+	// that changed in between
+	view := pipelinePB.View_VIEW_BASIC
+	if s.tracker.sweepDue(util.RESOURCE_TYPE_PIPELINE) {
+		view = pipelinePB.View_VIEW_FULL
+	}
+
+	return runProber[*pipelinePB.Pipeline](ctx, s, &pipelineProber{s: s, view: view})
+}
 
-	var wg sync.WaitGroup
+// pipelineProber derives the state of pipelines from the stored states of
+// their components, unless the user desires them inactive
+type pipelineProber struct {
+	s    *service
+	view pipelinePB.View
+}
 
-	resp, err := s.pipelinePrivateClient.ListPipelinesAdmin(ctx, &pipelinePB.ListPipelinesAdminRequest{
-		View: pipelinePB.View_VIEW_FULL.Enum(),
-	})
+func (p *pipelineProber) ResourceType() string {
+	return util.RESOURCE_TYPE_PIPELINE
+}
 
+func (p *pipelineProber) Backend() string {
+	return util.SERVICE_PIPELINE_BACKEND
+}
+
+func (p *pipelineProber) List(ctx context.Context, pageSize int64, pageToken string) ([]*pipelinePB.Pipeline, string, error) {
+	if p.s.pipelinePrivateClient == nil {
+		return nil, "", newClientUnavailableError(p.Backend())
+	}
+	resp, err := p.s.pipelinePrivateClient.ListPipelinesAdmin(ctx, &pipelinePB.ListPipelinesAdminRequest{
+		PageSize:  &pageSize,
+		PageToken: util.PageTokenOrNil(pageToken),
+		View:      p.view.Enum(),
+	})
 	if err != nil {
-		return err
+		return nil, "", err
 	}
+	return resp.Pipelines, resp.NextPageToken, nil
+}
 
-	pipelines := resp.Pipelines
-	nextPageToken := &resp.NextPageToken
-	totalSize := resp.TotalSize
+func (p *pipelineProber) UID(pipeline *pipelinePB.Pipeline) string {
+	return pipeline.Uid
+}
 
-	for totalSize > util.DefaultPageSize {
-		resp, err := s.pipelinePrivateClient.ListPipelinesAdmin(ctx, &pipelinePB.ListPipelinesAdminRequest{
-			PageToken: nextPageToken,
-			View:      pipelinePB.View_VIEW_FULL.Enum(),
-		})
+func (p *pipelineProber) Version(ctx context.Context, pipeline *pipelinePB.Pipeline) string {
+	version := resourceVersion(pipeline.UpdateTime, pipeline.State)
+	if pipeline.State == pipelinePB.Pipeline_STATE_INACTIVE {
+		return version
+	}
 
-		if err != nil {
+	// the state also changes with the states of the components
+	components, ok := p.s.tracker.getComponents(util.ConvertUIDToResourcePermalink(pipeline.Uid, p.ResourceType()))
+	if !ok {
+		return ""
+	}
+	return version + "|" + p.s.tracker.dependencyVersion(components)
+}
+
+// Desired returns whether the user activated or deactivated a pipeline, an
+// inactive pipeline is not derived from its components
+func (p *pipelineProber) Desired(pipeline *pipelinePB.Pipeline) (*controllerPB.Resource, bool) {
+	desired := pipelinePB.Pipeline_STATE_ACTIVE
+	if pipeline.State == pipelinePB.Pipeline_STATE_INACTIVE {
+		desired = pipelinePB.Pipeline_STATE_INACTIVE
+	}
+	return &controllerPB.Resource{
+		State: &controllerPB.Resource_PipelineState{
+			PipelineState: desired,
+		},
+	}, desired == pipelinePB.Pipeline_STATE_INACTIVE
+}
+
+func (p *pipelineProber) Check(ctx context.Context, pipeline *pipelinePB.Pipeline) ([]*controllerPB.Resource, error) {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if pipeline.Recipe == nil {
+		var resp *pipelinePB.LookUpPipelineAdminResponse
+		if err := p.s.breakers[p.Backend()].Do(func() (err error) {
+			if p.s.pipelinePrivateClient == nil {
+				return newClientUnavailableError(p.Backend())
+			}
+			resp, err = p.s.pipelinePrivateClient.LookUpPipelineAdmin(ctx, &pipelinePB.LookUpPipelineAdminRequest{
+				Permalink: fmt.Sprintf("%s/%s", p.ResourceType(), pipeline.Uid),
+				View:      pipelinePB.View_VIEW_FULL.Enum(),
+			})
 			return err
+		}); err != nil {
+			return nil, err
 		}
+		pipeline.Recipe = resp.Pipeline.Recipe
+	}
 
-		nextPageToken = &resp.NextPageToken
-		totalSize -= util.DefaultPageSize
-		pipelines = append(pipelines, resp.Pipelines...)
+	components := make([]string, 0, len(pipeline.Recipe.Components))
+	for _, component := range pipeline.Recipe.Components {
+		components = append(components, component.ResourceName)
 	}
+	p.s.tracker.setComponents(util.ConvertUIDToResourcePermalink(pipeline.Uid, p.ResourceType()), components)
 
-	resourceType := "pipelines"
+	// the component states are stale while their backend is down
+	for _, component := range pipeline.Recipe.Components {
+		if b := p.s.componentBreaker(component.ResourceName); b != nil && b.IsOpen() {
+			return nil, fmt.Errorf("%s is down: %w", b.Name(), breaker.ErrOpen)
+		}
+	}
 
-	wg.Add(len(pipelines))
+	resources := []*controllerPB.Resource{}
+
+	for _, component := range pipeline.Recipe.Components {
+
+		if i := strings.Index(component.ResourceName, "/"); i >= 0 {
+			componentType := component.ResourceName[:i]
+			switch componentType {
+			case util.RESOURCE_TYPE_SOURCE_CONNECTOR, util.RESOURCE_TYPE_DESTINATION_CONNECTOR, util.RESOURCE_TYPE_MODEL:
+				componentPermalink := util.ConvertUIDToResourcePermalink(component.ResourceName[i+1:], componentType)
+				resource, err := p.s.GetResourceState(ctx, componentPermalink)
+				switch {
+				case status.Code(err) == codes.NotFound:
+					logger.Error(fmt.Sprintf("no record found for %s in etcd", component.ResourceName))
+					// a component without record puts the pipeline in error
+					resource = &controllerPB.Resource{
+						ResourcePermalink: componentPermalink,
+						State: &controllerPB.Resource_ConnectorState{
+							ConnectorState: connectorPB.Connector_STATE_ERROR,
+						},
+					}
+				case err != nil:
+					return nil, err
+				}
+				resources = append(resources, resource)
+			}
+		}
 
-	for _, pipeline := range pipelines {
+	}
 
-		go func(pipeline *pipelinePB.Pipeline) {
-			defer wg.Done()
+	return resources, nil
+}
 
-			resourcePermalink := util.ConvertUIDToResourcePermalink(pipeline.Uid, resourceType)
+func (p *pipelineProber) Derive(pipeline *pipelinePB.Pipeline, observed []*controllerPB.Resource) *controllerPB.Resource {
+	pipelineResource := &controllerPB.Resource{
+		State: &controllerPB.Resource_PipelineState{
+			PipelineState: pipelinePB.Pipeline_STATE_ACTIVE,
+		},
+	}
 
-			pipelineResource := controllerPB.Resource{
-				ResourcePermalink: resourcePermalink,
-				State: &controllerPB.Resource_PipelineState{
+	for _, r := range observed {
+		switch v := r.State.(type) {
+		case *controllerPB.Resource_ConnectorState:
+			switch v.ConnectorState {
+			case connectorPB.Connector_STATE_DISCONNECTED:
+				pipelineResource.State = &controllerPB.Resource_PipelineState{
 					PipelineState: pipelinePB.Pipeline_STATE_INACTIVE,
-				},
-			}
-
-			// user desires inactive
-			if pipeline.State == pipelinePB.Pipeline_STATE_INACTIVE {
-				if err := s.UpdateResourceState(ctx, &pipelineResource); err != nil {
-					logger.Error(err.Error())
-					return
-				} else {
-					return
 				}
-			}
-
-			// user desires active, now check each component's state
-			pipelineResource.State = &controllerPB.Resource_PipelineState{PipelineState: pipelinePB.Pipeline_STATE_ERROR}
-
-			var resources []*controllerPB.Resource
-
-			for _, component := range pipeline.Recipe.Components {
-
-				if i := strings.Index(component.ResourceName, "/"); i >= 0 {
-					switch component.ResourceName[:i] {
-					case "source-connectors":
-						sourceConnectorResource, err := s.GetResourceState(ctx, util.ConvertUIDToResourcePermalink(strings.Split(component.ResourceName, "/")[1], "source-connectors"))
-						if err != nil {
-							resErr := s.UpdateResourceState(ctx, &pipelineResource)
-							if resErr != nil {
-								logger.Error(fmt.Sprintf("UpdateResourceState failed for %s", component.ResourceName))
-							}
-							logger.Error(fmt.Sprintf("no record found for %s in etcd", component.ResourceName))
-							return
-						}
-						resources = append(resources, sourceConnectorResource)
-					case "destination-connectors":
-						destinationConnectorResource, err := s.GetResourceState(ctx, util.ConvertUIDToResourcePermalink(strings.Split(component.ResourceName, "/")[1], "destination-connectors"))
-						if err != nil {
-							resErr := s.UpdateResourceState(ctx, &pipelineResource)
-							if resErr != nil {
-								logger.Error(fmt.Sprintf("UpdateResourceState failed for %s", component.ResourceName))
-							}
-							logger.Error(fmt.Sprintf("no record found for %s in etcd", component.ResourceName))
-							return
-						}
-						resources = append(resources, destinationConnectorResource)
-					case "models":
-						modelResource, err := s.GetResourceState(ctx, util.ConvertUIDToResourcePermalink(strings.Split(component.ResourceName, "/")[1], "models"))
-						if err != nil {
-							resErr := s.UpdateResourceState(ctx, &pipelineResource)
-							if resErr != nil {
-								logger.Error(fmt.Sprintf("UpdateResourceState failed for %s", component.ResourceName))
-							}
-							logger.Error(fmt.Sprintf("no record found for %s in etcd", component.ResourceName))
-							return
-						}
-
-						resources = append(resources, modelResource)
-					}
+			case connectorPB.Connector_STATE_UNSPECIFIED:
+				pipelineResource.State = &controllerPB.Resource_PipelineState{
+					PipelineState: pipelinePB.Pipeline_STATE_UNSPECIFIED,
 				}
-
+			case connectorPB.Connector_STATE_ERROR:
+				pipelineResource.State = &controllerPB.Resource_PipelineState{
+					PipelineState: pipelinePB.Pipeline_STATE_ERROR,
+				}
+			default:
+				continue
 			}
-
-			for _, r := range resources {
-				switch v := r.State.(type) {
-				case *controllerPB.Resource_ConnectorState:
-					switch v.ConnectorState {
-					case connectorPB.Connector_STATE_DISCONNECTED:
-						pipelineResource.State = &controllerPB.Resource_PipelineState{
-							PipelineState: pipelinePB.Pipeline_STATE_INACTIVE,
-						}
-					case connectorPB.Connector_STATE_UNSPECIFIED:
-						pipelineResource.State = &controllerPB.Resource_PipelineState{
-							PipelineState: pipelinePB.Pipeline_STATE_UNSPECIFIED,
-						}
-					case connectorPB.Connector_STATE_ERROR:
-						pipelineResource.State = &controllerPB.Resource_PipelineState{
-							PipelineState: pipelinePB.Pipeline_STATE_ERROR,
-						}
-					default:
-						continue
-					}
-				case *controllerPB.Resource_ModelState:
-					switch v.ModelState {
-					case modelPB.Model_STATE_OFFLINE:
-						pipelineResource.State = &controllerPB.Resource_PipelineState{
-							PipelineState: pipelinePB.Pipeline_STATE_INACTIVE,
-						}
-					case modelPB.Model_STATE_UNSPECIFIED:
-						pipelineResource.State = &controllerPB.Resource_PipelineState{
-							PipelineState: pipelinePB.Pipeline_STATE_UNSPECIFIED,
-						}
-					case modelPB.Model_STATE_ERROR:
-						pipelineResource.State = &controllerPB.Resource_PipelineState{
-							PipelineState: pipelinePB.Pipeline_STATE_ERROR,
-						}
-					default:
-						continue
-					}
+		case *controllerPB.Resource_ModelState:
+			switch v.ModelState {
+			case modelPB.Model_STATE_OFFLINE:
+				pipelineResource.State = &controllerPB.Resource_PipelineState{
+					PipelineState: pipelinePB.Pipeline_STATE_INACTIVE,
 				}
-				resErr := s.UpdateResourceState(ctx, &pipelineResource)
-				if resErr != nil {
-					logger.Error(fmt.Sprintf("UpdateResourceState failed for %s", pipeline.Name))
+			case modelPB.Model_STATE_UNSPECIFIED:
+				pipelineResource.State = &controllerPB.Resource_PipelineState{
+					PipelineState: pipelinePB.Pipeline_STATE_UNSPECIFIED,
 				}
-				return
+			case modelPB.Model_STATE_ERROR:
+				pipelineResource.State = &controllerPB.Resource_PipelineState{
+					PipelineState: pipelinePB.Pipeline_STATE_ERROR,
+				}
+			default:
+				continue
 			}
+		}
+		return pipelineResource
+	}
 
-			pipelineResource.State = &controllerPB.Resource_PipelineState{
-				PipelineState: pipelinePB.Pipeline_STATE_ACTIVE,
-			}
-			resErr := s.UpdateResourceState(ctx, &pipelineResource)
-			if resErr != nil {
-				logger.Error(fmt.Sprintf("UpdateResourceState failed for %s", pipeline.Name))
-			}
+	return pipelineResource
+}
 
-			logResp, _ := s.GetResourceState(ctx, resourcePermalink)
-			logger.Info(fmt.Sprintf("[Controller] Got %v", logResp))
-		}(pipeline)
+func (p *pipelineProber) Conditions(pipeline *pipelinePB.Pipeline, observed []*controllerPB.Resource) []Condition {
+	dependenciesReady := Condition{
+		Type:   ConditionDependenciesReady,
+		Status: ConditionTrue,
 	}
 
-	wg.Wait()
+	for _, r := range observed {
+		ready := true
+		state := ""
+		switch v := r.State.(type) {
+		case *controllerPB.Resource_ConnectorState:
+			ready = v.ConnectorState == connectorPB.Connector_STATE_CONNECTED
+			state = v.ConnectorState.String()
+		case *controllerPB.Resource_ModelState:
+			ready = v.ModelState == modelPB.Model_STATE_ONLINE
+			state = v.ModelState.String()
+		}
+		if !ready {
+			dependenciesReady.Status = ConditionFalse
+			dependenciesReady.Reason = "ComponentNotReady"
+			dependenciesReady.Message = fmt.Sprintf("%s is %s", r.ResourcePermalink, state)
+			break
+		}
+	}
 
-	return nil
+	return []Condition{dependenciesReady}
+}
+
+// componentBreaker returns the circuit breaker of the backend owning the
+// resource of a pipeline component
+func (s *service) componentBreaker(resourceName string) *breaker.Breaker {
+	switch strings.SplitN(resourceName, "/", 2)[0] {
+	case util.RESOURCE_TYPE_MODEL:
+		return s.breakers[util.SERVICE_MODEL_BACKEND]
+	case util.RESOURCE_TYPE_SOURCE_CONNECTOR, util.RESOURCE_TYPE_DESTINATION_CONNECTOR:
+		return s.breakers[util.SERVICE_CONNECTOR_BACKEND]
+	default:
+		return nil
+	}
 }
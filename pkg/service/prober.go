@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/breaker"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/util"
+	"github.com/instill-ai/controller/pkg/logger"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+// Prober probes the resources of one type owned by a backend service.
+// runProber takes care of listing, fan-out, timeouts, circuit breaking,
+// incremental evaluation, state updates, logging and metrics.
+type Prober[T any] interface {
+	// ResourceType returns the type of the probed resources, e.g. "models"
+	ResourceType() string
+	// Backend returns the name of the backend service owning the resources
+	Backend() string
+	// List lists a page of resources
+	List(ctx context.Context, pageSize int64, pageToken string) ([]T, string, error)
+	// UID returns the uid of a resource
+	UID(resource T) string
+	// Version identifies the revision of a resource, the resources whose
+	// non-empty version did not change are only evaluated on full sweeps
+	Version(ctx context.Context, resource T) string
+	// Desired returns the state desired by the user, or nil if there is
+	// none, and whether it settles the state of a resource without checking
+	// it
+	Desired(resource T) (*controllerPB.Resource, bool)
+	// Check returns the observed states the state of a resource is derived
+	// from, or nil if the resource is left as is. Calls to a backend must
+	// go through its circuit breaker, whose breaker.ErrOpen is reported as
+	// an unknown state.
+	Check(ctx context.Context, resource T) ([]*controllerPB.Resource, error)
+	// Derive derives the state of a resource from its observed states
+	Derive(resource T, observed []*controllerPB.Resource) *controllerPB.Resource
+}
+
+// outcomes of probing a resource
+const (
+	probeOutcomeSkipped = "skipped"
+	probeOutcomeSettled = "settled"
+	probeOutcomeChecked = "checked"
+	probeOutcomePending = "pending"
+	probeOutcomeUnknown = "unknown"
+	probeOutcomeFailed  = "failed"
+)
+
+type probeMetrics struct {
+	resources metric.Int64Counter
+	duration  metric.Float64Histogram
+}
+
+func newProbeMetrics() *probeMetrics {
+	meter := otel.Meter("controller.service.meter")
+	noopMeter := noop.NewMeterProvider().Meter("")
+
+	resources, err := meter.Int64Counter(
+		"controller.probe.resources",
+		metric.WithDescription("Number of probed resources by outcome"),
+	)
+	if err != nil {
+		resources, _ = noopMeter.Int64Counter("")
+	}
+
+	duration, err := meter.Float64Histogram(
+		"controller.probe.duration",
+		metric.WithDescription("Duration of a probe of every resource of a type"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		duration, _ = noopMeter.Float64Histogram("")
+	}
+
+	return &probeMetrics{
+		resources: resources,
+		duration:  duration,
+	}
+}
+
+// runProber probes every resource listed by a prober and updates their state
+func runProber[T any](ctx context.Context, s *service, p Prober[T]) error {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	start := time.Now()
+
+	resourceType := p.ResourceType()
+	backendBreaker := s.breakers[p.Backend()]
+
+	fullSweep := s.tracker.sweepDue(resourceType)
+
+	concurrency := config.Config.Server.ProbeConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	err := util.Paginate(ctx, config.Config.Server.PageSize, func(ctx context.Context, pageSize int64, pageToken string) ([]T, string, error) {
+		var resources []T
+		var nextPageToken string
+		err := backendBreaker.Do(func() (err error) {
+			resources, nextPageToken, err = p.List(ctx, pageSize, pageToken)
+			return err
+		})
+		return resources, nextPageToken, err
+	}, func(resources []T) {
+		for _, resource := range resources {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			wg.Add(1)
+			go func(resource T) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				outcome := probeResource(ctx, s, p, resource, fullSweep)
+				s.probeMetrics.resources.Add(ctx, 1, metric.WithAttributes(
+					attribute.String("resource_type", resourceType),
+					attribute.String("outcome", outcome),
+				))
+			}(resource)
+		}
+	})
+
+	wg.Wait()
+
+	s.probeMetrics.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("resource_type", resourceType),
+		attribute.Bool("full_sweep", fullSweep),
+		attribute.Bool("success", err == nil),
+	))
+
+	if errors.Is(err, breaker.ErrOpen) {
+		return s.markResourcesUnknown(ctx, resourceType, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if fullSweep {
+		s.tracker.sweepDone(resourceType)
+	}
+
+	logger.Debug(fmt.Sprintf("[Controller] probed %s in %v", resourceType, time.Since(start)))
+
+	return nil
+}
+
+// probeResource evaluates and stores the state of a resource, returning the
+// outcome of the evaluation
+func probeResource[T any](ctx context.Context, s *service, p Prober[T], resource T, fullSweep bool) string {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resourcePermalink := util.ConvertUIDToResourcePermalink(p.UID(resource), p.ResourceType())
+
+	if !fullSweep && s.tracker.unchanged(resourcePermalink, p.Version(ctx, resource)) {
+		return probeOutcomeSkipped
+	}
+	s.tracker.invalidate(resourcePermalink)
+
+	desired, settled := p.Desired(resource)
+	if desired != nil {
+		desired.ResourcePermalink = resourcePermalink
+		if err := s.UpdateResourceDesiredState(ctx, desired); err != nil {
+			logger.Error(err.Error())
+			return probeOutcomeFailed
+		}
+	}
+
+	outcome := probeOutcomeSettled
+	var state *controllerPB.Resource
+	var conditions []Condition
+
+	if settled {
+		state = desired
+		conditions = []Condition{{
+			Type:    ConditionBackendReachable,
+			Status:  ConditionUnknown,
+			Reason:  "NotChecked",
+			Message: "the state desired by the user is not checked",
+		}}
+	} else {
+		checkCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := config.Config.Server.ProbeCheckTimeout * time.Second; timeout > 0 {
+			checkCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		observed, err := p.Check(checkCtx, resource)
+		cancel()
+
+		switch {
+		case errors.Is(err, breaker.ErrOpen):
+			// the state is unknown while the backend is down
+			logger.Debug(fmt.Sprintf("[Controller] %v, %s is reported as unknown", err, resourcePermalink))
+			outcome = probeOutcomeUnknown
+			state = newResource(p.ResourceType(), 0)
+			conditions = []Condition{newCondition(ConditionBackendReachable, err, "CircuitOpen")}
+		case err != nil:
+			logger.Error(err.Error())
+			if err := s.UpdateResourceConditions(ctx, resourcePermalink, []Condition{newCondition(ConditionBackendReachable, err, "CheckFailed")}); err != nil {
+				logger.Error(err.Error())
+			}
+			return probeOutcomeFailed
+		case observed == nil:
+			outcome = probeOutcomePending
+		default:
+			outcome = probeOutcomeChecked
+			state = p.Derive(resource, observed)
+		}
+
+		if outcome != probeOutcomeUnknown {
+			conditions = []Condition{newCondition(ConditionBackendReachable, nil, "")}
+			if reporter, ok := p.(ConditionReporter[T]); ok {
+				conditions = append(conditions, reporter.Conditions(resource, observed)...)
+			}
+		}
+	}
+
+	if state != nil {
+		state.ResourcePermalink = resourcePermalink
+		if err := s.UpdateResourceState(ctx, state); err != nil {
+			logger.Error(err.Error())
+			return probeOutcomeFailed
+		}
+	}
+
+	if err := s.UpdateResourceConditions(ctx, resourcePermalink, conditions); err != nil {
+		logger.Error(err.Error())
+	}
+
+	if outcome == probeOutcomeSettled || outcome == probeOutcomeChecked {
+		s.tracker.evaluated(resourcePermalink, p.Version(ctx, resource))
+	}
+
+	logResp, _ := s.GetResourceState(ctx, resourcePermalink)
+	logger.Info(fmt.Sprintf("[Controller] Got %v", logResp))
+
+	return outcome
+}
+
+// newResource returns a resource of the given type in the given state, the
+// unspecified state being 0 for every type
+func newResource(resourceType string, state int32) *controllerPB.Resource {
+	switch resourceType {
+	case util.RESOURCE_TYPE_MODEL:
+		return &controllerPB.Resource{
+			State: &controllerPB.Resource_ModelState{
+				ModelState: modelPB.Model_State(state),
+			},
+		}
+	case util.RESOURCE_TYPE_PIPELINE:
+		return &controllerPB.Resource{
+			State: &controllerPB.Resource_PipelineState{
+				PipelineState: pipelinePB.Pipeline_State(state),
+			},
+		}
+	case util.RESOURCE_TYPE_SOURCE_CONNECTOR, util.RESOURCE_TYPE_DESTINATION_CONNECTOR:
+		return &controllerPB.Resource{
+			State: &controllerPB.Resource_ConnectorState{
+				ConnectorState: connectorPB.Connector_State(state),
+			},
+		}
+	case util.RESOURCE_TYPE_SERVICE:
+		return &controllerPB.Resource{
+			State: &controllerPB.Resource_BackendState{
+				BackendState: healthcheckPB.HealthCheckResponse_ServingStatus(state),
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// ResourceStateName returns the enum name of the state of a resource
+func ResourceStateName(resource *controllerPB.Resource) string {
+	switch v := resource.GetState().(type) {
+	case *controllerPB.Resource_ModelState:
+		return v.ModelState.String()
+	case *controllerPB.Resource_PipelineState:
+		return v.PipelineState.String()
+	case *controllerPB.Resource_ConnectorState:
+		return v.ConnectorState.String()
+	case *controllerPB.Resource_BackendState:
+		return v.BackendState.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,496 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/pkg/service"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+// storedStates records the states the probes write to etcd and the keys
+// they read
+type storedStates struct {
+	mu     sync.Mutex
+	values map[string]string
+	reads  map[string]bool
+}
+
+func (s *storedStates) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *storedStates) read(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reads[key]
+}
+
+// allowResourceUpdates lets the probes read and write the states and
+// conditions of any resource, nothing being stored beforehand, and returns
+// the states written
+func allowResourceUpdates(ctrl *gomock.Controller, mockKV *MockKV) *storedStates {
+	states := &storedStates{values: map[string]string{}, reads: map[string]bool{}}
+
+	mockKV.
+		EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, value string, opts ...etcdv3.OpOption) (*etcdv3.PutResponse, error) {
+			states.mu.Lock()
+			defer states.mu.Unlock()
+
+			states.values[key] = value
+			return &etcdv3.PutResponse{}, nil
+		}).
+		AnyTimes()
+
+	mockKV.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, opts ...etcdv3.OpOption) (*etcdv3.GetResponse, error) {
+			states.mu.Lock()
+			defer states.mu.Unlock()
+
+			states.reads[key] = true
+			return &etcdv3.GetResponse{}, nil
+		}).
+		AnyTimes()
+
+	mockKV.
+		EXPECT().
+		Txn(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) etcdv3.Txn {
+			mockTxn := NewMockTxn(ctrl)
+			mockTxn.EXPECT().If(gomock.Any()).Return(mockTxn)
+			mockTxn.EXPECT().Then(gomock.Any()).Return(mockTxn)
+			mockTxn.EXPECT().Commit().Return(&etcdv3.TxnResponse{
+				Header:    &etcdserverpb.ResponseHeader{},
+				Succeeded: true,
+			}, nil)
+			return mockTxn
+		}).
+		AnyTimes()
+
+	return states
+}
+
+// newProberEtcdClient returns an etcd client whose KV is mocked
+func newProberEtcdClient(ctrl *gomock.Controller, mockKV *MockKV) etcdv3.Client {
+	return etcdv3.Client{
+		Cluster:     NewMockCluster(ctrl),
+		KV:          mockKV,
+		Lease:       NewMockLease(ctrl),
+		Watcher:     NewMockWatcher(ctrl),
+		Auth:        NewMockAuth(ctrl),
+		Maintenance: NewMockMaintenance(ctrl),
+	}
+}
+
+func TestProbeConnectors(t *testing.T) {
+	const (
+		connectedUID    = "b6fd4b2b-6d3f-4c2f-8c3f-1e4d5f6a7b8c"
+		disconnectedUID = "d8af6d4d-8f5b-4e4b-ae5b-3a6f7b8c9d0e"
+	)
+
+	connected := fmt.Sprint(int32(connectorPB.Connector_STATE_CONNECTED))
+	disconnected := fmt.Sprint(int32(connectorPB.Connector_STATE_DISCONNECTED))
+
+	for _, tc := range []struct {
+		name         string
+		resourceType string
+		// expect expects the connectors to be listed and the connected one
+		// only to be checked
+		expect func(mockConnectorPrivateClient *MockConnectorPrivateServiceClient)
+		probe  func(s service.Service) error
+	}{
+		{
+			name:         "source connectors",
+			resourceType: "source-connectors",
+			expect: func(mockConnectorPrivateClient *MockConnectorPrivateServiceClient) {
+				mockConnectorPrivateClient.
+					EXPECT().
+					ListSourceConnectorsAdmin(gomock.Any(), gomock.Any()).
+					Return(&connectorPB.ListSourceConnectorsAdminResponse{
+						SourceConnectors: []*connectorPB.SourceConnector{
+							{Uid: connectedUID, Connector: &connectorPB.Connector{State: connectorPB.Connector_STATE_CONNECTED}},
+							{Uid: disconnectedUID, Connector: &connectorPB.Connector{State: connectorPB.Connector_STATE_DISCONNECTED}},
+						},
+					}, nil).
+					Times(1)
+				mockConnectorPrivateClient.
+					EXPECT().
+					CheckSourceConnector(gomock.Any(), &connectorPB.CheckSourceConnectorRequest{
+						SourceConnectorPermalink: "source-connectors/" + connectedUID,
+					}).
+					Return(&connectorPB.CheckSourceConnectorResponse{State: connectorPB.Connector_STATE_CONNECTED}, nil).
+					Times(1)
+			},
+			probe: func(s service.Service) error {
+				return s.ProbeSourceConnectors(context.WithCancel(context.Background()))
+			},
+		},
+		{
+			name:         "destination connectors",
+			resourceType: "destination-connectors",
+			expect: func(mockConnectorPrivateClient *MockConnectorPrivateServiceClient) {
+				mockConnectorPrivateClient.
+					EXPECT().
+					ListDestinationConnectorsAdmin(gomock.Any(), gomock.Any()).
+					Return(&connectorPB.ListDestinationConnectorsAdminResponse{
+						DestinationConnectors: []*connectorPB.DestinationConnector{
+							{Uid: connectedUID, Connector: &connectorPB.Connector{State: connectorPB.Connector_STATE_CONNECTED}},
+							{Uid: disconnectedUID, Connector: &connectorPB.Connector{State: connectorPB.Connector_STATE_DISCONNECTED}},
+						},
+					}, nil).
+					Times(1)
+				mockConnectorPrivateClient.
+					EXPECT().
+					CheckDestinationConnector(gomock.Any(), &connectorPB.CheckDestinationConnectorRequest{
+						DestinationConnectorPermalink: "destination-connectors/" + connectedUID,
+					}).
+					Return(&connectorPB.CheckDestinationConnectorResponse{State: connectorPB.Connector_STATE_CONNECTED}, nil).
+					Times(1)
+			},
+			probe: func(s service.Service) error {
+				return s.ProbeDestinationConnectors(context.WithCancel(context.Background()))
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockKV := NewMockKV(ctrl)
+			mockConnectorPrivateClient := NewMockConnectorPrivateServiceClient(ctrl)
+
+			states := allowResourceUpdates(ctrl, mockKV)
+
+			// the disconnected connector is never checked, connector-backend
+			// spawning a container to do so
+			tc.expect(mockConnectorPrivateClient)
+
+			s := service.NewService(newProberEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, mockConnectorPrivateClient, nil)
+
+			require.NoError(t, tc.probe(s))
+
+			state, ok := states.get(fmt.Sprintf("resources/%s/types/%s", connectedUID, tc.resourceType))
+			require.True(t, ok)
+			assert.Equal(t, connected, state)
+
+			// the disconnected connector settles in the state desired by the user
+			state, ok = states.get(fmt.Sprintf("resources/%s/types/%s", disconnectedUID, tc.resourceType))
+			require.True(t, ok)
+			assert.Equal(t, disconnected, state)
+			state, ok = states.get(fmt.Sprintf("resources/%s/types/%s/desired", disconnectedUID, tc.resourceType))
+			require.True(t, ok)
+			assert.Equal(t, disconnected, state)
+		})
+	}
+}
+
+// connectedSourceConnectors returns connected source connectors of the given uids
+func connectedSourceConnectors(uids ...string) []*connectorPB.SourceConnector {
+	connectors := make([]*connectorPB.SourceConnector, 0, len(uids))
+	for _, uid := range uids {
+		connectors = append(connectors, &connectorPB.SourceConnector{
+			Uid:       uid,
+			Connector: &connectorPB.Connector{State: connectorPB.Connector_STATE_CONNECTED},
+		})
+	}
+	return connectors
+}
+
+func TestRunProber(t *testing.T) {
+	serverConfig := config.Config.Server
+	t.Cleanup(func() {
+		config.Config.Server = serverConfig
+	})
+
+	connected := fmt.Sprint(int32(connectorPB.Connector_STATE_CONNECTED))
+
+	uids := []string{
+		"11111111-1111-4111-8111-111111111111",
+		"22222222-2222-4222-8222-222222222222",
+		"33333333-3333-4333-8333-333333333333",
+		"44444444-4444-4444-8444-444444444444",
+		"55555555-5555-4555-8555-555555555555",
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		config.Config.Server = serverConfig
+		config.Config.Server.PageSize = 2
+
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+		mockConnectorPrivateClient := NewMockConnectorPrivateServiceClient(ctrl)
+
+		states := allowResourceUpdates(ctrl, mockKV)
+
+		// the pages are listed in turn, each with the page token of the
+		// previous one
+		gomock.InOrder(
+			mockConnectorPrivateClient.
+				EXPECT().
+				ListSourceConnectorsAdmin(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, req *connectorPB.ListSourceConnectorsAdminRequest, opts ...grpc.CallOption) (*connectorPB.ListSourceConnectorsAdminResponse, error) {
+					assert.Equal(t, int64(2), req.GetPageSize())
+					assert.Nil(t, req.PageToken)
+					return &connectorPB.ListSourceConnectorsAdminResponse{
+						SourceConnectors: connectedSourceConnectors(uids[0], uids[1]),
+						NextPageToken:    "page-2",
+					}, nil
+				}),
+			mockConnectorPrivateClient.
+				EXPECT().
+				ListSourceConnectorsAdmin(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, req *connectorPB.ListSourceConnectorsAdminRequest, opts ...grpc.CallOption) (*connectorPB.ListSourceConnectorsAdminResponse, error) {
+					assert.Equal(t, int64(2), req.GetPageSize())
+					assert.Equal(t, "page-2", req.GetPageToken())
+					return &connectorPB.ListSourceConnectorsAdminResponse{
+						SourceConnectors: connectedSourceConnectors(uids[2]),
+					}, nil
+				}),
+		)
+
+		mockConnectorPrivateClient.
+			EXPECT().
+			CheckSourceConnector(gomock.Any(), gomock.Any()).
+			Return(&connectorPB.CheckSourceConnectorResponse{State: connectorPB.Connector_STATE_CONNECTED}, nil).
+			Times(3)
+
+		s := service.NewService(newProberEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, mockConnectorPrivateClient, nil)
+
+		require.NoError(t, s.ProbeSourceConnectors(context.WithCancel(context.Background())))
+
+		for _, uid := range uids[:3] {
+			state, ok := states.get(fmt.Sprintf("resources/%s/types/source-connectors", uid))
+			require.True(t, ok)
+			assert.Equal(t, connected, state)
+		}
+	})
+
+	t.Run("concurrency bound", func(t *testing.T) {
+		config.Config.Server = serverConfig
+		config.Config.Server.ProbeConcurrency = 2
+
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+		mockConnectorPrivateClient := NewMockConnectorPrivateServiceClient(ctrl)
+
+		allowResourceUpdates(ctrl, mockKV)
+
+		mockConnectorPrivateClient.
+			EXPECT().
+			ListSourceConnectorsAdmin(gomock.Any(), gomock.Any()).
+			Return(&connectorPB.ListSourceConnectorsAdminResponse{
+				SourceConnectors: connectedSourceConnectors(uids...),
+			}, nil).
+			Times(1)
+
+		var inFlight atomic.Int32
+		release := make(chan struct{})
+		mockConnectorPrivateClient.
+			EXPECT().
+			CheckSourceConnector(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *connectorPB.CheckSourceConnectorRequest, opts ...grpc.CallOption) (*connectorPB.CheckSourceConnectorResponse, error) {
+				inFlight.Add(1)
+				defer inFlight.Add(-1)
+				<-release
+				return &connectorPB.CheckSourceConnectorResponse{State: connectorPB.Connector_STATE_CONNECTED}, nil
+			}).
+			Times(len(uids))
+
+		s := service.NewService(newProberEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, mockConnectorPrivateClient, nil)
+
+		done := make(chan error)
+		go func() {
+			done <- s.ProbeSourceConnectors(context.WithCancel(context.Background()))
+		}()
+
+		// no more checks than the bound run at once
+		require.Eventually(t, func() bool {
+			return inFlight.Load() == 2
+		}, 5*time.Second, time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(2), inFlight.Load())
+
+		close(release)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("check timeout", func(t *testing.T) {
+		config.Config.Server = serverConfig
+		config.Config.Server.ProbeCheckTimeout = 1
+		config.Config.Server.CircuitBreaker.Threshold = 10
+
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+		mockConnectorPrivateClient := NewMockConnectorPrivateServiceClient(ctrl)
+
+		states := allowResourceUpdates(ctrl, mockKV)
+
+		mockConnectorPrivateClient.
+			EXPECT().
+			ListSourceConnectorsAdmin(gomock.Any(), gomock.Any()).
+			Return(&connectorPB.ListSourceConnectorsAdminResponse{
+				SourceConnectors: connectedSourceConnectors(uids[0], uids[1]),
+			}, nil).
+			Times(1)
+
+		// the first check hangs until its timeout, the second one is
+		// still made
+		mockConnectorPrivateClient.
+			EXPECT().
+			CheckSourceConnector(gomock.Any(), &connectorPB.CheckSourceConnectorRequest{
+				SourceConnectorPermalink: "source-connectors/" + uids[0],
+			}).
+			DoAndReturn(func(ctx context.Context, req *connectorPB.CheckSourceConnectorRequest, opts ...grpc.CallOption) (*connectorPB.CheckSourceConnectorResponse, error) {
+				<-ctx.Done()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}).
+			Times(1)
+		mockConnectorPrivateClient.
+			EXPECT().
+			CheckSourceConnector(gomock.Any(), &connectorPB.CheckSourceConnectorRequest{
+				SourceConnectorPermalink: "source-connectors/" + uids[1],
+			}).
+			Return(&connectorPB.CheckSourceConnectorResponse{State: connectorPB.Connector_STATE_CONNECTED}, nil).
+			Times(1)
+
+		s := service.NewService(newProberEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, mockConnectorPrivateClient, nil)
+
+		start := time.Now()
+		require.NoError(t, s.ProbeSourceConnectors(context.WithCancel(context.Background())))
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		// the state of the connector whose check timed out is left as is
+		_, ok := states.get(fmt.Sprintf("resources/%s/types/source-connectors", uids[0]))
+		assert.False(t, ok)
+		state, ok := states.get(fmt.Sprintf("resources/%s/types/source-connectors", uids[1]))
+		require.True(t, ok)
+		assert.Equal(t, connected, state)
+	})
+}
+
+func TestCircuitOpen(t *testing.T) {
+	serverConfig := config.Config.Server
+	t.Cleanup(func() {
+		config.Config.Server = serverConfig
+	})
+
+	const (
+		modelUID    = "a5ec3a1a-5c2f-4b1e-9b2f-0d3c4e5f6a7b"
+		pipelineUID = "c70e5c3c-7e4a-4d3a-9d4a-2f5e6a7b8c9d"
+	)
+	modelKey := fmt.Sprintf("resources/%s/types/models", modelUID)
+	pipelineKey := fmt.Sprintf("resources/%s/types/pipelines", pipelineUID)
+
+	// openModelBreaker opens the model-backend breaker by failing to list the
+	// models without a client
+	openModelBreaker := func(t *testing.T, s service.Service) {
+		require.Error(t, s.ProbeModels(context.WithCancel(context.Background())))
+	}
+
+	t.Run("resources marked unknown", func(t *testing.T) {
+		config.Config.Server = serverConfig
+		config.Config.Server.CircuitBreaker.Threshold = 1
+		config.Config.Server.CircuitBreaker.Cooldown = 60
+
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+
+		mockKV.
+			EXPECT().
+			Get(gomock.Any(), "resources/", gomock.Any()).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{
+					{Key: []byte(modelKey), Value: []byte(fmt.Sprint(int32(modelPB.Model_STATE_ONLINE)))},
+					{Key: []byte(pipelineKey), Value: []byte(fmt.Sprint(int32(pipelinePB.Pipeline_STATE_ACTIVE)))},
+				},
+			}, nil).
+			Times(1)
+
+		states := allowResourceUpdates(ctrl, mockKV)
+
+		s := service.NewService(newProberEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		openModelBreaker(t, s)
+
+		// the models are not listed while the breaker is open, their stored
+		// states being reported as unknown
+		require.NoError(t, s.ProbeModels(context.WithCancel(context.Background())))
+
+		state, ok := states.get(modelKey)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprint(int32(modelPB.Model_STATE_UNSPECIFIED)), state)
+
+		_, ok = states.get(pipelineKey)
+		assert.False(t, ok)
+	})
+
+	t.Run("pipeline check skipped", func(t *testing.T) {
+		config.Config.Server = serverConfig
+		config.Config.Server.CircuitBreaker.Threshold = 1
+		config.Config.Server.CircuitBreaker.Cooldown = 60
+
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+		mockPipelinePrivateClient := NewMockPipelinePrivateServiceClient(ctrl)
+
+		states := allowResourceUpdates(ctrl, mockKV)
+
+		mockPipelinePrivateClient.
+			EXPECT().
+			ListPipelinesAdmin(gomock.Any(), gomock.Any()).
+			Return(&pipelinePB.ListPipelinesAdminResponse{
+				Pipelines: []*pipelinePB.Pipeline{{
+					Uid:   pipelineUID,
+					State: pipelinePB.Pipeline_STATE_ACTIVE,
+					Recipe: &pipelinePB.Recipe{
+						Components: []*pipelinePB.Component{{ResourceName: "models/" + modelUID}},
+					},
+				}},
+			}, nil).
+			Times(1)
+
+		s := service.NewService(newProberEtcdClient(ctrl, mockKV), nil, nil, nil, nil, nil, mockPipelinePrivateClient, nil, nil, nil)
+
+		openModelBreaker(t, s)
+
+		require.NoError(t, s.ProbePipelines(context.WithCancel(context.Background())))
+
+		// the stale state of the model is not derived from
+		assert.False(t, states.read(modelKey))
+
+		state, ok := states.get(pipelineKey)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprint(int32(pipelinePB.Pipeline_STATE_UNSPECIFIED)), state)
+	})
+}
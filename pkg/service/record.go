@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/controller/internal/util"
+
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+)
+
+// ResourceRecord is what the controller knows about a resource
+type ResourceRecord struct {
+	// Observed is the state observed by the controller, or reported by the
+	// backend owning the resource
+	Observed *controllerPB.Resource
+	// Desired is the state the user turned the resource to, nil for the
+	// resources that cannot be turned on and off such as backend services
+	Desired *controllerPB.Resource
+	// Conditions tell why the resource is in its observed state
+	Conditions []Condition
+}
+
+// InSync reports whether the observed state of a resource is the desired one,
+// e.g. an offline model is in sync if the user undeployed it, and out of sync
+// if it failed
+func (r *ResourceRecord) InSync() bool {
+	if r.Desired == nil {
+		return true
+	}
+
+	// the states of a malformed permalink cannot be compared
+	permalink, err := util.ParseResourcePermalink(r.Observed.ResourcePermalink)
+	if err != nil {
+		return false
+	}
+
+	observed, _ := resourceStateValue(permalink.Type, r.Observed)
+	desired, _ := resourceStateValue(permalink.Type, r.Desired)
+
+	return observed == desired
+}
+
+func (s *service) GetResourceRecord(ctx context.Context, resourcePermalink string) (*ResourceRecord, error) {
+	observed, err := s.GetResourceState(ctx, resourcePermalink)
+	if err != nil {
+		return nil, err
+	}
+
+	// not every resource has a desired state
+	desired, err := s.GetResourceDesiredState(ctx, resourcePermalink)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	conditions, err := s.GetResourceConditions(ctx, resourcePermalink)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceRecord{
+		Observed:   observed,
+		Desired:    desired,
+		Conditions: conditions,
+	}, nil
+}
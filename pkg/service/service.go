@@ -6,19 +6,25 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	etcdv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
 
 	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/breaker"
+	"github.com/instill-ai/controller/internal/external"
+	"github.com/instill-ai/controller/internal/notifier"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
 	"github.com/instill-ai/controller/internal/triton"
 	"github.com/instill-ai/controller/internal/util"
 	"github.com/instill-ai/controller/pkg/logger"
 
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
 	mgmtPB "github.com/instill-ai/protogen-go/vdp/mgmt/v1alpha"
 	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
@@ -27,16 +33,30 @@ import (
 
 type Service interface {
 	GetResourceState(ctx context.Context, resourcePermalink string) (*controllerPB.Resource, error)
+	GetResourceRecord(ctx context.Context, resourcePermalink string) (*ResourceRecord, error)
+	GetResourceConditions(ctx context.Context, resourcePermalink string) ([]Condition, error)
+	UpdateResourceConditions(ctx context.Context, resourcePermalink string, conditions []Condition) error
+	DeleteResourceConditions(ctx context.Context, resourcePermalink string) error
 	UpdateResourceState(ctx context.Context, resource *controllerPB.Resource) error
 	DeleteResourceState(ctx context.Context, resourcePermalink string) error
 	GetResourceWorkflowId(ctx context.Context, resourcePermalink string) (*string, error)
 	UpdateResourceWorkflowId(ctx context.Context, resourcePermalink string, workflowId string) error
 	DeleteResourceWorkflowId(ctx context.Context, resourcePermalink string) error
+	GetResourceDesiredState(ctx context.Context, resourcePermalink string) (*controllerPB.Resource, error)
+	UpdateResourceDesiredState(ctx context.Context, resource *controllerPB.Resource) error
+	DeleteResourceDesiredState(ctx context.Context, resourcePermalink string) error
+	GetSystemHealth(ctx context.Context) (*SystemHealth, error)
 	ProbeBackend(ctx context.Context, cancel context.CancelFunc) error
 	ProbeModels(ctx context.Context, cancel context.CancelFunc) error
 	ProbeSourceConnectors(ctx context.Context, cancel context.CancelFunc) error
 	ProbeDestinationConnectors(ctx context.Context, cancel context.CancelFunc) error
 	ProbePipelines(ctx context.Context, cancel context.CancelFunc) error
+	RecordProbeCycle(t time.Time)
+	SetPopulated(populated bool)
+	CheckHealth(ctx context.Context) error
+	WaitForStartup(ctx context.Context) error
+	WatchResources(ctx context.Context) error
+	Close(ctx context.Context) error
 }
 
 type service struct {
@@ -49,6 +69,19 @@ type service struct {
 	pipelinePrivateClient  pipelinePB.PipelinePrivateServiceClient
 	connectorPublicClient  connectorPB.ConnectorPublicServiceClient
 	connectorPrivateClient connectorPB.ConnectorPrivateServiceClient
+	clients                *external.ClientManager
+	healthConnsMu          sync.Mutex
+	healthConns            map[string]*grpc.ClientConn
+	breakers               map[string]*breaker.Breaker
+	lastProbeCycle         atomic.Int64
+	populated              atomic.Bool
+	startupPhase           atomic.Value
+	now                    func() time.Time
+	tracker                *probeTracker
+	notifier               notifier.Notifier
+	cache                  stateCache
+	snapshot               *resourceSnapshot
+	probeMetrics           *probeMetrics
 }
 
 func NewService(
@@ -60,8 +93,11 @@ func NewService(
 	p pipelinePB.PipelinePublicServiceClient,
 	pp pipelinePB.PipelinePrivateServiceClient,
 	c connectorPB.ConnectorPublicServiceClient,
-	cp connectorPB.ConnectorPrivateServiceClient) Service {
-	return &service{
+	cp connectorPB.ConnectorPrivateServiceClient,
+	cm *external.ClientManager) Service {
+	redisClient := newRedisClient()
+
+	s := &service{
 		etcdClient:             e,
 		tritonClient:           t,
 		mgmtPublicClient:       mg,
@@ -71,27 +107,81 @@ func NewService(
 		pipelinePrivateClient:  pp,
 		connectorPublicClient:  c,
 		connectorPrivateClient: cp,
+		clients:                cm,
+		healthConns:            map[string]*grpc.ClientConn{},
+		breakers:               newBreakers(),
+		now:                    time.Now,
+		tracker:                newProbeTracker(time.Now),
+		notifier: notifier.NewMultiNotifier(
+			notifier.NewWebhookNotifier(config.Config.Notifier),
+			notifier.NewPublisher(config.Config.Notifier.Publisher, redisClient),
+		),
+		cache:        newStateCache(redisClient),
+		snapshot:     newResourceSnapshot(),
+		probeMetrics: newProbeMetrics(),
 	}
+	s.startupPhase.Store(startupPhaseStarting)
+
+	return s
 }
 
-func (s *service) GetResourceState(ctx context.Context, resourcePermalink string) (*controllerPB.Resource, error) {
-	resp, err := s.etcdClient.Get(ctx, resourcePermalink)
+func newBreakers() map[string]*breaker.Breaker {
+	breakers := map[string]*breaker.Breaker{}
+	for _, name := range []string{
+		util.SERVICE_TRITON_SERVER,
+		util.SERVICE_CONNECTOR_BACKEND,
+		util.SERVICE_MODEL_BACKEND,
+		util.SERVICE_PIPELINE_BACKEND,
+		util.SERVICE_MGMT_BACKEND,
+	} {
+		breakers[name] = breaker.New(
+			name,
+			config.Config.Server.CircuitBreaker.Threshold,
+			config.Config.Server.CircuitBreaker.Cooldown*time.Second,
+		)
+	}
+	return breakers
+}
 
+// GetResourceState returns the stored state of a resource, read from the
+// snapshot while it is up to date. Redis is only read while the snapshot is
+// not ready, e.g. at startup or while the watch is re-established, so that a
+// new replica is spared an etcd read per request; a miss falls back to etcd
+func (s *service) GetResourceState(ctx context.Context, resourcePermalink string) (*controllerPB.Resource, error) {
+	permalink, err := util.ParseRequestPermalink("resource_permalink", resourcePermalink)
 	if err != nil {
 		return nil, err
 	}
 
-	kvs := resp.Kvs
+	var value string
 
-	if len(kvs) == 0 {
-		return nil, fmt.Errorf(fmt.Sprintf("resource %v not found in etcd storage", resourcePermalink))
-	}
+	if stored, found, ready := s.snapshot.get(resourcePermalink); ready {
+		if !found {
+			return nil, newNotFoundError(resourcePermalink, fmt.Sprintf("resource %v not found in etcd storage", resourcePermalink))
+		}
+		value = string(stored)
+	} else if cached, ok := s.cache.Get(ctx, resourcePermalink); ok {
+		value = cached
+	} else {
+		resp, err := s.etcdClient.Get(ctx, resourcePermalink)
+
+		if err != nil {
+			return nil, storageError(err)
+		}
 
-	resourceType := strings.SplitN(resourcePermalink, "/", 4)[3]
+		kvs := resp.Kvs
 
-	stateEnumValue, _ := strconv.ParseInt(string(kvs[0].Value[:]), 10, 32)
+		if len(kvs) == 0 {
+			return nil, newNotFoundError(resourcePermalink, fmt.Sprintf("resource %v not found in etcd storage", resourcePermalink))
+		}
 
-	switch resourceType {
+		value = string(kvs[0].Value[:])
+		s.cache.Set(ctx, resourcePermalink, value, kvs[0].ModRevision)
+	}
+
+	stateEnumValue, _ := strconv.ParseInt(value, 10, 32)
+
+	switch resourceType := permalink.Type; resourceType {
 	case util.RESOURCE_TYPE_MODEL:
 		return &controllerPB.Resource{
 			ResourcePermalink: resourcePermalink,
@@ -124,61 +214,70 @@ func (s *service) GetResourceState(ctx context.Context, resourcePermalink string
 			},
 		}, nil
 	default:
-		return nil, fmt.Errorf(fmt.Sprintf("get resource type %s not implemented", resourceType))
+		return nil, util.NewInvalidArgumentError("resource_permalink", fmt.Sprintf("get resource type %s not implemented", resourceType))
 	}
 }
 
 func (s *service) UpdateResourceState(ctx context.Context, resource *controllerPB.Resource) error {
-	resourceType := strings.SplitN(resource.ResourcePermalink, "/", 4)[3]
+	permalink, err := util.ParseRequestPermalink("resource.resource_permalink", resource.ResourcePermalink)
+	if err != nil {
+		return err
+	}
+	resourceType := permalink.Type
 
-	state := 0
+	state, ok := resourceStateValue(resourceType, resource)
+	if !ok {
+		return util.NewInvalidArgumentError("resource.resource_permalink", fmt.Sprintf("update resource type %s not implemented", resourceType))
+	}
 
-	switch resourceType {
-	case util.RESOURCE_TYPE_MODEL:
-		state = int(resource.GetModelState())
-	case util.RESOURCE_TYPE_PIPELINE:
-		state = int(resource.GetPipelineState())
-	case util.RESOURCE_TYPE_SOURCE_CONNECTOR, util.RESOURCE_TYPE_DESTINATION_CONNECTOR:
-		state = int(resource.GetConnectorState())
-	case util.RESOURCE_TYPE_SERVICE:
-		state = int(resource.GetBackendState())
-	default:
-		return fmt.Errorf(fmt.Sprintf("update resource type %s not implemented", resourceType))
+	// the previous state is only needed to notify transitions
+	var opts []etcdv3.OpOption
+	if s.notifier.Enabled() {
+		opts = append(opts, etcdv3.WithPrevKV())
 	}
 
-	if _, err := s.etcdClient.Put(ctx, resource.ResourcePermalink, fmt.Sprint(state)); err != nil {
+	resp, err := s.putValue(ctx, resource.ResourcePermalink, fmt.Sprint(state), opts...)
+	if err != nil {
 		return err
 	}
 
+	s.tracker.setState(resource.ResourcePermalink, state)
+	s.cache.Delete(ctx, resource.ResourcePermalink, responseRevision(resp.Header))
+
+	if s.notifier.Enabled() {
+		s.notifyTransition(ctx, resource.ResourcePermalink, resourceType, state, resp.PrevKv)
+	}
+
 	return nil
 }
 
 func (s *service) DeleteResourceState(ctx context.Context, resourcePermalink string) error {
-	_, err := s.etcdClient.Delete(ctx, resourcePermalink)
+	revision, err := s.deleteValue(ctx, resourcePermalink)
 
 	if err != nil {
 		return err
 	}
 
+	s.tracker.forget(resourcePermalink)
+	s.cache.Delete(ctx, resourcePermalink, revision)
+
 	return nil
 }
 
 func (s *service) GetResourceWorkflowId(ctx context.Context, resourcePermalink string) (*string, error) {
 	resourceWorkflowId := util.ConvertResourcePermalinkToWorkflowName(resourcePermalink)
 
-	resp, err := s.etcdClient.Get(ctx, resourceWorkflowId)
+	value, found, err := s.getValue(ctx, resourceWorkflowId)
 
 	if err != nil {
 		return nil, err
 	}
 
-	kvs := resp.Kvs
-
-	if len(kvs) == 0 {
-		return nil, fmt.Errorf("workflowId not found in etcd storage")
+	if !found {
+		return nil, newNotFoundError(resourcePermalink, "workflowId not found in etcd storage")
 	}
 
-	workflowId := string(kvs[0].Value[:])
+	workflowId := string(value)
 
 	return &workflowId, nil
 }
@@ -186,7 +285,7 @@ func (s *service) GetResourceWorkflowId(ctx context.Context, resourcePermalink s
 func (s *service) UpdateResourceWorkflowId(ctx context.Context, resourcePermalink string, workflowId string) error {
 	resourceWorkflowId := util.ConvertResourcePermalinkToWorkflowName(resourcePermalink)
 
-	_, err := s.etcdClient.Put(ctx, resourceWorkflowId, workflowId)
+	_, err := s.putValue(ctx, resourceWorkflowId, workflowId)
 
 	if err != nil {
 		return err
@@ -198,7 +297,7 @@ func (s *service) UpdateResourceWorkflowId(ctx context.Context, resourcePermalin
 func (s *service) DeleteResourceWorkflowId(ctx context.Context, resourcePermalink string) error {
 	resourceWorkflowId := util.ConvertResourcePermalinkToWorkflowName(resourcePermalink)
 
-	_, err := s.etcdClient.Delete(ctx, resourceWorkflowId)
+	_, err := s.deleteValue(ctx, resourceWorkflowId)
 
 	if err != nil {
 		return err
@@ -207,115 +306,106 @@ func (s *service) DeleteResourceWorkflowId(ctx context.Context, resourcePermalin
 	return nil
 }
 
-func (s *service) ProbeBackend(ctx context.Context, cancel context.CancelFunc) error {
-	defer cancel()
-
-	logger, _ := logger.GetZapLogger(ctx)
+func (s *service) GetResourceDesiredState(ctx context.Context, resourcePermalink string) (*controllerPB.Resource, error) {
+	permalink, err := util.ParseRequestPermalink("resource_permalink", resourcePermalink)
+	if err != nil {
+		return nil, err
+	}
 
-	var wg sync.WaitGroup
+	value, found, err := s.getValue(ctx, util.ConvertResourcePermalinkToDesiredStateName(resourcePermalink))
 
-	healthcheck := healthcheckPB.HealthCheckResponse{
-		Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED,
+	if err != nil {
+		return nil, err
 	}
 
-	var backenServices = [...]string{
-		config.Config.TritonServer.Host,
-		config.Config.ConnectorBackend.Host,
-		config.Config.ModelBackend.Host,
-		config.Config.PipelineBackend.Host,
-		config.Config.MgmtBackend.Host,
+	if !found {
+		return nil, newNotFoundError(resourcePermalink, "desired state not found in etcd storage")
 	}
 
-	wg.Add(len(backenServices))
-
-	for _, hostname := range backenServices {
-		go func(hostname string) {
-			defer wg.Done()
-
-			switch hostname {
-			case config.Config.TritonServer.Host:
-				resp, err := s.tritonClient.ServerLive(ctx, &inferenceserver.ServerLiveRequest{})
-
-				if err != nil {
-					healthcheck = healthcheckPB.HealthCheckResponse{
-						Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
-					}
-				} else {
-					if resp.GetLive() {
-						healthcheck = healthcheckPB.HealthCheckResponse{
-							Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
-						}
-					} else {
-						healthcheck = healthcheckPB.HealthCheckResponse{
-							Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
-						}
-					}
-				}
-			case config.Config.ModelBackend.Host:
-				resp, err := s.modelPublicClient.Liveness(ctx, &modelPB.LivenessRequest{})
-
-				if err != nil {
-					healthcheck = healthcheckPB.HealthCheckResponse{
-						Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
-					}
-				} else {
-					healthcheck = *resp.GetHealthCheckResponse()
-				}
-			case config.Config.PipelineBackend.Host:
-				resp, err := s.pipelinePublicClient.Liveness(ctx, &pipelinePB.LivenessRequest{})
-
-				if err != nil {
-					healthcheck = healthcheckPB.HealthCheckResponse{
-						Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
-					}
-				} else {
-					healthcheck = *resp.GetHealthCheckResponse()
-				}
-			case config.Config.MgmtBackend.Host:
-				resp, err := s.mgmtPublicClient.Liveness(ctx, &mgmtPB.LivenessRequest{})
-
-				if err != nil {
-					healthcheck = healthcheckPB.HealthCheckResponse{
-						Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
-					}
-				} else {
-					healthcheck = *resp.GetHealthCheckResponse()
-				}
-			case config.Config.ConnectorBackend.Host:
-				resp, err := s.connectorPublicClient.Liveness(ctx, &connectorPB.LivenessRequest{})
-
-				if err != nil {
-					healthcheck = healthcheckPB.HealthCheckResponse{
-						Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING,
-					}
-				} else {
-					healthcheck = *resp.GetHealthCheckResponse()
-				}
-			}
+	stateEnumValue, _ := strconv.ParseInt(string(value), 10, 32)
 
-			err := s.UpdateResourceState(ctx, &controllerPB.Resource{
-				ResourcePermalink: util.ConvertServiceToResourceName(hostname),
-				State: &controllerPB.Resource_BackendState{
-					BackendState: healthcheck.Status,
-				},
-			})
+	resource := newResource(permalink.Type, int32(stateEnumValue))
+	if resource == nil {
+		return nil, util.NewInvalidArgumentError("resource_permalink", fmt.Sprintf("desired state not supported for %s", resourcePermalink))
+	}
+	resource.ResourcePermalink = resourcePermalink
 
-			if err != nil {
-				logger.Error(err.Error())
-				return
-			}
+	return resource, nil
+}
 
-			resp, _ := s.GetResourceState(ctx, util.ConvertServiceToResourceName(hostname))
+func (s *service) UpdateResourceDesiredState(ctx context.Context, resource *controllerPB.Resource) error {
+	permalink, err := util.ParseRequestPermalink("resource.resource_permalink", resource.ResourcePermalink)
+	if err != nil {
+		return err
+	}
+	resourceType := permalink.Type
 
-			logger.Info(fmt.Sprintf("[Controller] Got %v", resp))
-		}(hostname)
+	state, ok := resourceStateValue(resourceType, resource)
+	if !ok {
+		return util.NewInvalidArgumentError("resource.resource_permalink", fmt.Sprintf("update resource type %s not implemented", resourceType))
 	}
 
-	wg.Wait()
+	if _, err := s.putValue(ctx, util.ConvertResourcePermalinkToDesiredStateName(resource.ResourcePermalink), fmt.Sprint(state)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteResourceDesiredState(ctx context.Context, resourcePermalink string) error {
+	_, err := s.deleteValue(ctx, util.ConvertResourcePermalinkToDesiredStateName(resourcePermalink))
+
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// notifyTransition notifies the transition of a resource to a new state, a
+// resource without previous record being new
+func (s *service) notifyTransition(ctx context.Context, resourcePermalink string, resourceType string, state int, prevKv *mvccpb.KeyValue) {
+	event := notifier.Event{
+		ResourcePermalink: resourcePermalink,
+		ResourceType:      resourceType,
+		State:             ResourceStateName(newResource(resourceType, int32(state))),
+		Time:              time.Now().UTC(),
+	}
+
+	if prevKv != nil {
+		previous, _ := strconv.ParseInt(string(prevKv.Value), 10, 32)
+		if int(previous) == state {
+			return
+		}
+		event.PreviousState = ResourceStateName(newResource(resourceType, int32(previous)))
+	}
+
+	s.notifier.Notify(ctx, event)
+}
+
+// Close waits for the pending notifications of state transitions until ctx
+// is done
+func (s *service) Close(ctx context.Context) error {
+	return s.notifier.Close(ctx)
+}
+
+// resourceStateValue returns the enum value of the state of a resource of
+// the given type, and false if the type is not supported
+func resourceStateValue(resourceType string, resource *controllerPB.Resource) (int, bool) {
+	switch resourceType {
+	case util.RESOURCE_TYPE_MODEL:
+		return int(resource.GetModelState()), true
+	case util.RESOURCE_TYPE_PIPELINE:
+		return int(resource.GetPipelineState()), true
+	case util.RESOURCE_TYPE_SOURCE_CONNECTOR, util.RESOURCE_TYPE_DESTINATION_CONNECTOR:
+		return int(resource.GetConnectorState()), true
+	case util.RESOURCE_TYPE_SERVICE:
+		return int(resource.GetBackendState()), true
+	default:
+		return 0, false
+	}
+}
+
 func (s *service) getOperationInfo(workflowId string, resourceType string) (*longrunningpb.Operation, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), config.Config.Server.Timeout*time.Second)
 	defer cancel()
@@ -324,14 +414,53 @@ func (s *service) getOperationInfo(workflowId string, resourceType string) (*lon
 
 	switch resourceType {
 	case util.RESOURCE_TYPE_MODEL:
-		op, err := s.modelPublicClient.GetModelOperation(ctx, &modelPB.GetModelOperationRequest{
-			Name: fmt.Sprintf("operations/%s", workflowId),
-		})
-		if err != nil {
+		if err := s.breakers[util.SERVICE_MODEL_BACKEND].Do(func() error {
+			if s.modelPublicClient == nil {
+				return newClientUnavailableError(util.SERVICE_MODEL_BACKEND)
+			}
+			op, err := s.modelPublicClient.GetModelOperation(ctx, &modelPB.GetModelOperationRequest{
+				Name: fmt.Sprintf("operations/%s", workflowId),
+			})
+			if err != nil {
+				return err
+			}
+			operation = op.Operation
+			return nil
+		}); err != nil {
 			return nil, err
 		}
-		operation = op.Operation
 	}
 
 	return operation, nil
 }
+
+// markResourcesUnknown sets the state of every stored resource of the given
+// type to unspecified, used when the backend owning them is down
+func (s *service) markResourcesUnknown(ctx context.Context, resourceType string, reason error) error {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	logger.Warn(fmt.Sprintf("[Controller] %v, %s are reported as unknown", reason, resourceType))
+
+	kvs, err := s.listValues(ctx, "resources/")
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range kvs {
+		parts := strings.Split(string(kv.Key), "/")
+		if len(parts) != 4 || parts[2] != "types" || parts[3] != resourceType {
+			continue
+		}
+		unknown := newResource(resourceType, 0)
+		unknown.ResourcePermalink = string(kv.Key)
+		if err := s.UpdateResourceState(ctx, unknown); err != nil {
+			return err
+		}
+		s.tracker.invalidate(string(kv.Key))
+		if err := s.UpdateResourceConditions(ctx, string(kv.Key), []Condition{newCondition(ConditionBackendReachable, reason, "CircuitOpen")}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,24 +2,40 @@ package service_test
 
 import (
 	"context"
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/instill-ai/controller/config"
+	"github.com/instill-ai/controller/internal/external"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
 	"github.com/instill-ai/controller/pkg/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
-	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
 	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
 	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	etcdv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 const serviceResourceName = "resources/name/types/services"
-const modelResourceName = "resources/name/types/models"
-const connectorResourceName = "resources/name/types/source-connectors"
-const pipelineResourceName = "resources/name/types/pipelines"
+const modelResourceName = "resources/a5ec3a1a-5c2f-4b1e-9b2f-0d3c4e5f6a7b/types/models"
+const connectorResourceName = "resources/b6fd4b2b-6d3a-4c2f-8c3a-1e4d5f6a7b8c/types/source-connectors"
+const pipelineResourceName = "resources/c70e5c3c-7e4b-4d3a-9d4b-2f5e6a7b8c9d/types/pipelines"
 
 type Client struct {
 	etcdv3.Cluster
@@ -58,7 +74,9 @@ func TestGetResourceState(t *testing.T) {
 			Maintenance: mockMaintenance,
 		}
 
-		var resp *etcdv3.GetResponse
+		resp := &etcdv3.GetResponse{
+			Kvs: []*mvccpb.KeyValue{{Value: []byte("0")}},
+		}
 
 		mockKV.
 			EXPECT().
@@ -66,7 +84,7 @@ func TestGetResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		resource, err := s.GetResourceState(ctx, serviceResourceName)
 
@@ -93,7 +111,9 @@ func TestGetResourceState(t *testing.T) {
 			Maintenance: mockMaintenance,
 		}
 
-		var resp *etcdv3.GetResponse
+		resp := &etcdv3.GetResponse{
+			Kvs: []*mvccpb.KeyValue{{Value: []byte("0")}},
+		}
 
 		mockKV.
 			EXPECT().
@@ -101,7 +121,7 @@ func TestGetResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		resource, err := s.GetResourceState(ctx, modelResourceName)
 
@@ -128,7 +148,9 @@ func TestGetResourceState(t *testing.T) {
 			Maintenance: mockMaintenance,
 		}
 
-		var resp *etcdv3.GetResponse
+		resp := &etcdv3.GetResponse{
+			Kvs: []*mvccpb.KeyValue{{Value: []byte("0")}},
+		}
 
 		mockKV.
 			EXPECT().
@@ -136,7 +158,7 @@ func TestGetResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		resource, err := s.GetResourceState(ctx, connectorResourceName)
 
@@ -163,7 +185,9 @@ func TestGetResourceState(t *testing.T) {
 			Maintenance: mockMaintenance,
 		}
 
-		var resp *etcdv3.GetResponse
+		resp := &etcdv3.GetResponse{
+			Kvs: []*mvccpb.KeyValue{{Value: []byte("0")}},
+		}
 
 		mockKV.
 			EXPECT().
@@ -171,7 +195,7 @@ func TestGetResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		resource, err := s.GetResourceState(ctx, pipelineResourceName)
 
@@ -179,6 +203,82 @@ func TestGetResourceState(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		mockKV.
+			EXPECT().
+			Get(ctx, pipelineResourceName).
+			Return(&etcdv3.GetResponse{}, nil).
+			Times(1)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := s.GetResourceState(ctx, pipelineResourceName)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+	t.Run("etcd unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		mockKV.
+			EXPECT().
+			Get(ctx, pipelineResourceName).
+			Return(nil, rpctypes.ErrNoLeader).
+			Times(1)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		_, err := s.GetResourceState(ctx, pipelineResourceName)
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("malformed permalink", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          NewMockKV(ctrl),
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// rejected without reading etcd
+		for _, permalink := range []string{"resources/name", "models/name", "resources/name/types/models"} {
+			_, err := s.GetResourceState(ctx, permalink)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), permalink)
+
+			_, err = s.GetResourceDesiredState(ctx, permalink)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), permalink)
+		}
+	})
 }
 
 func TestUpdateResourceState(t *testing.T) {
@@ -216,7 +316,7 @@ func TestUpdateResourceState(t *testing.T) {
 			Return(&etcdv3.PutResponse{}, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.UpdateResourceState(ctx, &resource)
 
@@ -255,7 +355,7 @@ func TestUpdateResourceState(t *testing.T) {
 			Return(&etcdv3.PutResponse{}, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.UpdateResourceState(ctx, &resource)
 
@@ -294,7 +394,7 @@ func TestUpdateResourceState(t *testing.T) {
 			Return(&etcdv3.PutResponse{}, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.UpdateResourceState(ctx, &resource)
 
@@ -333,12 +433,38 @@ func TestUpdateResourceState(t *testing.T) {
 			Return(&etcdv3.PutResponse{}, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.UpdateResourceState(ctx, &resource)
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("malformed permalink", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          NewMockKV(ctrl),
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		resource := controllerPB.Resource{
+			ResourcePermalink: "resources/name",
+			State: &controllerPB.Resource_ModelState{
+				ModelState: modelPB.Model_STATE_ONLINE,
+			},
+		}
+
+		// rejected without writing to etcd
+		assert.Equal(t, codes.InvalidArgument, status.Code(s.UpdateResourceState(ctx, &resource)))
+		assert.Equal(t, codes.InvalidArgument, status.Code(s.UpdateResourceDesiredState(ctx, &resource)))
+	})
 }
 
 func TestDeleteResourceState(t *testing.T) {
@@ -371,7 +497,7 @@ func TestDeleteResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.DeleteResourceState(ctx, serviceResourceName)
 
@@ -404,7 +530,7 @@ func TestDeleteResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.DeleteResourceState(ctx, modelResourceName)
 
@@ -437,7 +563,7 @@ func TestDeleteResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.DeleteResourceState(ctx, connectorResourceName)
 
@@ -470,10 +596,552 @@ func TestDeleteResourceState(t *testing.T) {
 			Return(resp, nil).
 			Times(1)
 
-		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil)
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		err := s.DeleteResourceState(ctx, pipelineResourceName)
 
 		assert.NoError(t, err)
 	})
 }
+
+func TestProbeBackend(t *testing.T) {
+	backendServices := config.Config.BackendServices
+	t.Cleanup(func() {
+		config.Config.BackendServices = backendServices
+	})
+
+	t.Run("liveness", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockCluster := NewMockCluster(ctrl)
+		mockKV := NewMockKV(ctrl)
+		mockLease := NewMockLease(ctrl)
+		mockWatcher := NewMockWatcher(ctrl)
+		mockAuth := NewMockAuth(ctrl)
+		mockMaintenance := NewMockMaintenance(ctrl)
+		mockModelPublicClient := NewMockModelPublicServiceClient(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     mockCluster,
+			KV:          mockKV,
+			Lease:       mockLease,
+			Watcher:     mockWatcher,
+			Auth:        mockAuth,
+			Maintenance: mockMaintenance,
+		}
+
+		config.Config.BackendServices = []config.BackendServiceConfig{
+			{Name: "model-backend", Kind: config.ProbeKindLiveness},
+		}
+
+		mockModelPublicClient.
+			EXPECT().
+			Liveness(gomock.Any(), gomock.Any()).
+			Return(&modelPB.LivenessResponse{
+				HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
+					Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
+				},
+			}, nil).
+			Times(1)
+
+		mockKV.
+			EXPECT().
+			Put(gomock.Any(), "resources/model-backend/types/services", string("1")).
+			Return(&etcdv3.PutResponse{}, nil).
+			Times(1)
+
+		mockKV.
+			EXPECT().
+			Get(gomock.Any(), "resources/model-backend/types/services/conditions").
+			Return(&etcdv3.GetResponse{}, nil).
+			Times(1)
+
+		expectConditionsUpdate(ctrl, mockKV, "resources/model-backend/types/services/conditions", 0)
+
+		resp := &etcdv3.GetResponse{
+			Kvs: []*mvccpb.KeyValue{{Value: []byte("1")}},
+		}
+
+		mockKV.
+			EXPECT().
+			Get(gomock.Any(), "resources/model-backend/types/services").
+			Return(resp, nil).
+			Times(1)
+
+		s := service.NewService(mockEtcdClient, nil, nil, mockModelPublicClient, nil, nil, nil, nil, nil, nil)
+
+		err := s.ProbeBackend(context.WithCancel(context.Background()))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("client not available", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		config.Config.BackendServices = []config.BackendServiceConfig{
+			{Name: "model-backend", Kind: config.ProbeKindLiveness},
+		}
+
+		// the backend is reported as down instead of called through a nil client
+		mockKV.
+			EXPECT().
+			Put(gomock.Any(), "resources/model-backend/types/services", string("2")).
+			Return(&etcdv3.PutResponse{}, nil).
+			Times(1)
+
+		mockKV.
+			EXPECT().
+			Get(gomock.Any(), gomock.Any()).
+			Return(&etcdv3.GetResponse{}, nil).
+			AnyTimes()
+
+		expectConditionsUpdate(ctrl, mockKV, "resources/model-backend/types/services/conditions", 0)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		err := s.ProbeBackend(context.WithCancel(context.Background()))
+
+		assert.NoError(t, err)
+	})
+}
+
+// serveGRPCHealth serves a grpc.health.v1 server, none if nil, and returns a
+// ClientManager dialing it with the count of the connections dialed
+func serveGRPCHealth(t *testing.T, healthServer healthgrpc.HealthServer) (*external.ClientManager, *atomic.Int32) {
+	listener := bufconn.Listen(1 << 20)
+	grpcS := grpc.NewServer()
+	if healthServer != nil {
+		healthgrpc.RegisterHealthServer(grpcS, healthServer)
+	}
+	go func() {
+		_ = grpcS.Serve(listener)
+	}()
+	t.Cleanup(grpcS.Stop)
+
+	var dials atomic.Int32
+	clients := external.NewClientManager(context.Background(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		dials.Add(1)
+		return listener.DialContext(ctx)
+	}))
+	t.Cleanup(func() {
+		_ = clients.Close()
+	})
+
+	return clients, &dials
+}
+
+func TestProbeGRPCHealth(t *testing.T) {
+	backendServices := config.Config.BackendServices
+	t.Cleanup(func() {
+		config.Config.BackendServices = backendServices
+	})
+
+	for _, tc := range []struct {
+		name   string
+		status healthgrpc.HealthCheckResponse_ServingStatus
+		// unimplemented serves no health service
+		unimplemented bool
+		state         string
+	}{
+		{name: "serving", status: healthgrpc.HealthCheckResponse_SERVING, state: "1"},
+		{name: "not serving", status: healthgrpc.HealthCheckResponse_NOT_SERVING, state: "2"},
+		{name: "unimplemented", unimplemented: true, state: "2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockKV := NewMockKV(ctrl)
+
+			mockEtcdClient := etcdv3.Client{
+				Cluster:     NewMockCluster(ctrl),
+				KV:          mockKV,
+				Lease:       NewMockLease(ctrl),
+				Watcher:     NewMockWatcher(ctrl),
+				Auth:        NewMockAuth(ctrl),
+				Maintenance: NewMockMaintenance(ctrl),
+			}
+
+			var healthServer healthgrpc.HealthServer
+			if !tc.unimplemented {
+				server := health.NewServer()
+				server.SetServingStatus("redis", tc.status)
+				healthServer = server
+			}
+			clients, dials := serveGRPCHealth(t, healthServer)
+
+			config.Config.BackendServices = []config.BackendServiceConfig{
+				{Name: "redis-proxy", Kind: config.ProbeKindGRPCHealth, Address: "bufnet", Service: "redis"},
+			}
+
+			mockKV.
+				EXPECT().
+				Put(gomock.Any(), "resources/redis-proxy/types/services", tc.state).
+				Return(&etcdv3.PutResponse{}, nil).
+				Times(2)
+
+			mockKV.
+				EXPECT().
+				Get(gomock.Any(), gomock.Any()).
+				Return(&etcdv3.GetResponse{}, nil).
+				AnyTimes()
+
+			expectConditionsUpdate(ctrl, mockKV, "resources/redis-proxy/types/services/conditions", 0)
+			expectConditionsUpdate(ctrl, mockKV, "resources/redis-proxy/types/services/conditions", 0)
+
+			s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, clients)
+
+			for i := 0; i < 2; i++ {
+				require.NoError(t, s.ProbeBackend(context.WithCancel(context.Background())))
+			}
+
+			// the probes reuse the connection dialed by the first one
+			assert.Equal(t, int32(1), dials.Load())
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t.Run("etcd not connected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          NewMockKV(ctrl),
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		s.RecordProbeCycle(time.Now())
+
+		assert.Error(t, s.CheckHealth(ctx))
+	})
+}
+
+func TestWaitForStartup(t *testing.T) {
+	t.Run("etcd not connected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          NewMockKV(ctrl),
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, s.WaitForStartup(ctx), context.DeadlineExceeded)
+
+		err := s.CheckHealth(context.Background())
+		assert.ErrorContains(t, err, "waiting for the etcd connection")
+	})
+}
+
+func TestGetSystemHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backendServices := config.Config.BackendServices
+	t.Cleanup(func() {
+		config.Config.BackendServices = backendServices
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		config.Config.BackendServices = []config.BackendServiceConfig{
+			{Name: "model-backend", Kind: config.ProbeKindLiveness},
+		}
+
+		mockKV.
+			EXPECT().
+			Get(ctx, "resources/", gomock.Any()).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{
+					{Key: []byte("resources/model-backend/types/services"), Value: []byte("1")},
+					{Key: []byte("resources/a/types/models"), Value: []byte("2")},
+					{Key: []byte("resources/b/types/models"), Value: []byte("2")},
+					{Key: []byte("resources/b/types/models/workflow"), Value: []byte("id")},
+					{Key: []byte("resources/c/types/pipelines"), Value: []byte("3")},
+				},
+			}, nil).
+			Times(1)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		systemHealth, err := s.GetSystemHealth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), systemHealth.Models[modelPB.Model_STATE_ONLINE])
+		assert.Equal(t, int64(1), systemHealth.Pipelines[pipelinePB.Pipeline_STATE_ERROR])
+		assert.Equal(t, healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING, systemHealth.Services[0].GetBackendState())
+		// etcd is not connected in the test, so the controller itself is not serving
+		assert.Equal(t, healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, systemHealth.Status)
+	})
+
+	t.Run("backend connection failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     NewMockWatcher(ctrl),
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		config.Config.BackendServices = []config.BackendServiceConfig{
+			{Name: "model-backend", Kind: config.ProbeKindLiveness},
+		}
+
+		// the last probe found the backend serving
+		mockKV.
+			EXPECT().
+			Get(ctx, "resources/", gomock.Any()).
+			Return(&etcdv3.GetResponse{
+				Kvs: []*mvccpb.KeyValue{
+					{Key: []byte("resources/model-backend/types/services"), Value: []byte("1")},
+				},
+			}, nil).
+			Times(1)
+
+		clients := external.NewClientManager(ctx)
+		defer clients.Close()
+
+		clientConn, err := clients.Dial(ctx, "model-backend", "localhost:1", config.TLSConfig{})
+		require.NoError(t, err)
+		clientConn.Connect()
+		require.Eventually(t, func() bool {
+			return clients.States()["model-backend"] == connectivity.TransientFailure
+		}, 10*time.Second, 10*time.Millisecond)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, clients)
+
+		systemHealth, err := s.GetSystemHealth(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING, systemHealth.Services[0].GetBackendState())
+	})
+}
+
+func TestResourceRecordInSync(t *testing.T) {
+	t.Run("undeployed model", func(t *testing.T) {
+		record := service.ResourceRecord{
+			Observed: &controllerPB.Resource{
+				ResourcePermalink: modelResourceName,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_OFFLINE},
+			},
+			Desired: &controllerPB.Resource{
+				ResourcePermalink: modelResourceName,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_OFFLINE},
+			},
+		}
+
+		assert.True(t, record.InSync())
+	})
+	t.Run("failed model", func(t *testing.T) {
+		record := service.ResourceRecord{
+			Observed: &controllerPB.Resource{
+				ResourcePermalink: modelResourceName,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_OFFLINE},
+			},
+			Desired: &controllerPB.Resource{
+				ResourcePermalink: modelResourceName,
+				State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ONLINE},
+			},
+		}
+
+		assert.False(t, record.InSync())
+	})
+	t.Run("backend service", func(t *testing.T) {
+		record := service.ResourceRecord{
+			Observed: &controllerPB.Resource{
+				ResourcePermalink: serviceResourceName,
+				State:             &controllerPB.Resource_BackendState{BackendState: healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING},
+			},
+		}
+
+		assert.True(t, record.InSync())
+	})
+}
+
+func TestWatchResources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t.Run("reads served from the snapshot", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+		mockWatcher := NewMockWatcher(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     mockWatcher,
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		// the only read from etcd is the one loading the snapshot
+		mockKV.
+			EXPECT().
+			Get(ctx, "resources/", gomock.Any()).
+			Return(&etcdv3.GetResponse{
+				Header: &etcdserverpb.ResponseHeader{Revision: 5},
+				Kvs: []*mvccpb.KeyValue{
+					{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 4},
+				},
+			}, nil).
+			Times(1)
+
+		watchChan := make(chan etcdv3.WatchResponse)
+		mockWatcher.
+			EXPECT().
+			Watch(gomock.Any(), "resources/", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(etcdv3.WatchChan(watchChan)).
+			Times(1)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.WatchResources(ctx)
+		}()
+
+		watchChan <- etcdv3.WatchResponse{
+			Events: []*etcdv3.Event{{
+				Type: mvccpb.PUT,
+				Kv:   &mvccpb.KeyValue{Key: []byte(pipelineResourceName), Value: []byte("3"), ModRevision: 6},
+			}},
+		}
+		// the previous response is applied once the next one is received
+		watchChan <- etcdv3.WatchResponse{}
+
+		model, err := s.GetResourceState(ctx, modelResourceName)
+		assert.NoError(t, err)
+		assert.Equal(t, modelPB.Model_STATE_ONLINE, model.GetModelState())
+
+		pipeline, err := s.GetResourceState(ctx, pipelineResourceName)
+		assert.NoError(t, err)
+		assert.Equal(t, pipelinePB.Pipeline_STATE_ERROR, pipeline.GetPipelineState())
+
+		_, err = s.GetResourceState(ctx, connectorResourceName)
+		assert.Error(t, err)
+
+		close(watchChan)
+		<-done
+	})
+	t.Run("deleted key not brought back by a late write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockKV := NewMockKV(ctrl)
+		mockWatcher := NewMockWatcher(ctrl)
+
+		mockEtcdClient := etcdv3.Client{
+			Cluster:     NewMockCluster(ctrl),
+			KV:          mockKV,
+			Lease:       NewMockLease(ctrl),
+			Watcher:     mockWatcher,
+			Auth:        NewMockAuth(ctrl),
+			Maintenance: NewMockMaintenance(ctrl),
+		}
+
+		mockKV.
+			EXPECT().
+			Get(ctx, "resources/", gomock.Any()).
+			Return(&etcdv3.GetResponse{
+				Header: &etcdserverpb.ResponseHeader{Revision: 5},
+				Kvs: []*mvccpb.KeyValue{
+					{Key: []byte(modelResourceName), Value: []byte("2"), ModRevision: 4},
+				},
+			}, nil).
+			Times(1)
+
+		// the write of the controller was applied by etcd before the deletion
+		// of another replica, its response arriving after the watch event
+		mockKV.
+			EXPECT().
+			Put(ctx, modelResourceName, "3").
+			Return(&etcdv3.PutResponse{Header: &etcdserverpb.ResponseHeader{Revision: 6}}, nil).
+			Times(1)
+
+		watchChan := make(chan etcdv3.WatchResponse)
+		mockWatcher.
+			EXPECT().
+			Watch(gomock.Any(), "resources/", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(etcdv3.WatchChan(watchChan)).
+			Times(1)
+
+		s := service.NewService(mockEtcdClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.WatchResources(ctx)
+		}()
+
+		watchChan <- etcdv3.WatchResponse{
+			Events: []*etcdv3.Event{
+				{
+					Type: mvccpb.PUT,
+					Kv:   &mvccpb.KeyValue{Key: []byte(modelResourceName), Value: []byte("3"), ModRevision: 6},
+				},
+				{
+					Type: mvccpb.DELETE,
+					Kv:   &mvccpb.KeyValue{Key: []byte(modelResourceName), ModRevision: 7},
+				},
+			},
+		}
+		// the previous response is applied, and the deletion pruned, once the
+		// next one is received
+		watchChan <- etcdv3.WatchResponse{}
+
+		assert.NoError(t, s.UpdateResourceState(ctx, &controllerPB.Resource{
+			ResourcePermalink: modelResourceName,
+			State:             &controllerPB.Resource_ModelState{ModelState: modelPB.Model_STATE_ERROR},
+		}))
+
+		_, err := s.GetResourceState(ctx, modelResourceName)
+		assert.Error(t, err)
+
+		close(watchChan)
+		<-done
+	})
+}
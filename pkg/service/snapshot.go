@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/instill-ai/controller/pkg/logger"
+)
+
+// resourceSnapshot is an in-memory copy of the resources/ keyspace, loaded
+// from etcd and kept up to date by watching it, so that the reads of a
+// controller are served locally
+type resourceSnapshot struct {
+	mu    sync.RWMutex
+	ready bool
+	// revision is the etcd revision up to which every change is applied
+	revision int64
+	// entries are kept on deletion so that a late write cannot bring back a
+	// deleted key, until the watch reaches their revision
+	entries map[string]snapshotEntry
+}
+
+type snapshotEntry struct {
+	value       []byte
+	modRevision int64
+	deleted     bool
+}
+
+func newResourceSnapshot() *resourceSnapshot {
+	return &resourceSnapshot{
+		entries: map[string]snapshotEntry{},
+	}
+}
+
+// load replaces the snapshot with the keys read at a revision and makes it
+// ready
+func (c *resourceSnapshot) load(kvs []*mvccpb.KeyValue, revision int64) {
+	entries := make(map[string]snapshotEntry, len(kvs))
+	for _, kv := range kvs {
+		entries[string(kv.Key)] = snapshotEntry{value: kv.Value, modRevision: kv.ModRevision}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = entries
+	c.revision = revision
+	c.ready = true
+}
+
+// reset makes the snapshot unready until it is loaded again, the reads going
+// to etcd meanwhile
+func (c *resourceSnapshot) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]snapshotEntry{}
+	c.revision = 0
+	c.ready = false
+}
+
+// advance records that every change up to a revision is applied, pruning the
+// deleted keys at or below it
+func (c *resourceSnapshot) advance(revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.ready || revision <= c.revision {
+		return
+	}
+	c.revision = revision
+
+	for key, entry := range c.entries {
+		if entry.deleted && entry.modRevision <= revision {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// put records the value of a key written at a revision, unless a later write
+// is already recorded
+func (c *resourceSnapshot) put(key string, value []byte, revision int64) {
+	c.apply(key, snapshotEntry{value: value, modRevision: revision})
+}
+
+// delete records the deletion of a key at a revision, unless a later write is
+// already recorded
+func (c *resourceSnapshot) delete(key string, revision int64) {
+	c.apply(key, snapshotEntry{modRevision: revision, deleted: true})
+}
+
+func (c *resourceSnapshot) apply(key string, entry snapshotEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.ready {
+		return
+	}
+	current, ok := c.entries[key]
+	if ok && current.modRevision >= entry.modRevision {
+		return
+	}
+	// an absent key is up to date at the snapshot revision, its deletion
+	// being possibly pruned
+	if !ok && entry.modRevision <= c.revision {
+		return
+	}
+	c.entries[key] = entry
+}
+
+// get returns the value of a key and whether it exists, ready being false
+// while the snapshot cannot be trusted
+func (c *resourceSnapshot) get(key string) (value []byte, found bool, ready bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.ready {
+		return nil, false, false
+	}
+
+	entry, ok := c.entries[key]
+	if !ok || entry.deleted {
+		return nil, false, true
+	}
+	return entry.value, true, true
+}
+
+// list returns the existing keys under a prefix, ready being false while the
+// snapshot cannot be trusted
+func (c *resourceSnapshot) list(prefix string) (kvs []*mvccpb.KeyValue, ready bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.ready {
+		return nil, false
+	}
+
+	for key, entry := range c.entries {
+		if entry.deleted || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		kvs = append(kvs, &mvccpb.KeyValue{Key: []byte(key), Value: entry.value, ModRevision: entry.modRevision})
+	}
+	return kvs, true
+}
+
+// getValue returns the value of a key from the snapshot, or from etcd while
+// the snapshot is not ready
+func (s *service) getValue(ctx context.Context, key string) ([]byte, bool, error) {
+	if value, found, ready := s.snapshot.get(key); ready {
+		return value, found, nil
+	}
+
+	resp, err := s.etcdClient.Get(ctx, key)
+	if err != nil {
+		return nil, false, storageError(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, false, nil
+	}
+	return resp.Kvs[0].Value, true, nil
+}
+
+// listValues returns the keys under a prefix from the snapshot, or from etcd
+// while the snapshot is not ready
+func (s *service) listValues(ctx context.Context, prefix string) ([]*mvccpb.KeyValue, error) {
+	if kvs, ready := s.snapshot.list(prefix); ready {
+		return kvs, nil
+	}
+
+	resp, err := s.etcdClient.Get(ctx, prefix, etcdv3.WithPrefix())
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return resp.Kvs, nil
+}
+
+// putValue writes a key to etcd and to the snapshot, for the writes of the
+// controller to be read back before their watch event arrives
+func (s *service) putValue(ctx context.Context, key string, value string, opts ...etcdv3.OpOption) (*etcdv3.PutResponse, error) {
+	resp, err := s.etcdClient.Put(ctx, key, value, opts...)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	if resp != nil && resp.Header != nil {
+		s.snapshot.put(key, []byte(value), resp.Header.Revision)
+	}
+	return resp, nil
+}
+
+// deleteValue deletes a key from etcd and from the snapshot, returning the
+// revision of the deletion, 0 if unknown
+func (s *service) deleteValue(ctx context.Context, key string) (int64, error) {
+	resp, err := s.etcdClient.Delete(ctx, key)
+	if err != nil {
+		return 0, storageError(err)
+	}
+	if resp != nil && resp.Header != nil {
+		s.snapshot.delete(key, resp.Header.Revision)
+		return resp.Header.Revision, nil
+	}
+	return 0, nil
+}
+
+// responseRevision returns the revision of an etcd response, 0 if unknown
+func responseRevision(header *etcdserverpb.ResponseHeader) int64 {
+	if header == nil {
+		return 0
+	}
+	return header.Revision
+}
+
+// WatchResources loads the resources/ keyspace into the snapshot and keeps it
+// up to date, invalidating the cached resource states changed in etcd
+// including by other controller replicas, until the context is done or the
+// watch fails
+func (s *service) WatchResources(ctx context.Context) error {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	// the snapshot is stale as soon as the watch stops
+	defer s.snapshot.reset()
+
+	resp, err := s.etcdClient.Get(ctx, "resources/", etcdv3.WithPrefix())
+	if err != nil {
+		return fmt.Errorf("cannot load the resources: %w", err)
+	}
+	s.snapshot.load(resp.Kvs, resp.Header.Revision)
+
+	logger.Info(fmt.Sprintf("[Controller] loaded %d resource keys at revision %d", len(resp.Kvs), resp.Header.Revision))
+
+	// a watch requiring a leader is cancelled when the member is partitioned,
+	// instead of silently serving nothing
+	watchCtx := etcdv3.WithRequireLeader(ctx)
+	for watchResp := range s.etcdClient.Watch(watchCtx, "resources/", etcdv3.WithPrefix(), etcdv3.WithRev(resp.Header.Revision+1), etcdv3.WithProgressNotify()) {
+		if err := watchResp.Err(); err != nil {
+			return err
+		}
+		for _, event := range watchResp.Events {
+			key := string(event.Kv.Key)
+			switch event.Type {
+			case mvccpb.PUT:
+				s.snapshot.put(key, event.Kv.Value, event.Kv.ModRevision)
+			case mvccpb.DELETE:
+				s.snapshot.delete(key, event.Kv.ModRevision)
+			}
+			// only the state keys are cached
+			if parts := strings.Split(key, "/"); len(parts) == 4 {
+				s.cache.Delete(ctx, key, event.Kv.ModRevision)
+			}
+		}
+		// the events are ordered, a progress notification telling that there
+		// are none up to its revision
+		if n := len(watchResp.Events); n > 0 {
+			s.snapshot.advance(watchResp.Events[n-1].Kv.ModRevision)
+		} else if watchResp.IsProgressNotify() {
+			s.snapshot.advance(watchResp.Header.Revision)
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/instill-ai/controller/config"
+	controllerPB "github.com/instill-ai/controller/internal/pb/controller/v1alpha"
+	"github.com/instill-ai/controller/internal/util"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/vdp/healthcheck/v1alpha"
+	modelPB "github.com/instill-ai/protogen-go/vdp/model/v1alpha"
+	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
+)
+
+// SystemHealth is the aggregated health of the platform
+type SystemHealth struct {
+	// Status is the rolled-up status of the controller and all backend services
+	Status healthcheckPB.HealthCheckResponse_ServingStatus
+	// Services holds the last probed state of each backend service, not
+	// serving while the connections to it fail
+	Services []*controllerPB.Resource
+	// The number of stored resources per state for each resource type
+	Models                map[modelPB.Model_State]int64
+	SourceConnectors      map[connectorPB.Connector_State]int64
+	DestinationConnectors map[connectorPB.Connector_State]int64
+	Pipelines             map[pipelinePB.Pipeline_State]int64
+}
+
+func (s *service) GetSystemHealth(ctx context.Context) (*SystemHealth, error) {
+	kvs, err := s.listValues(ctx, "resources/")
+	if err != nil {
+		return nil, err
+	}
+
+	systemHealth := SystemHealth{
+		Models:                map[modelPB.Model_State]int64{},
+		SourceConnectors:      map[connectorPB.Connector_State]int64{},
+		DestinationConnectors: map[connectorPB.Connector_State]int64{},
+		Pipelines:             map[pipelinePB.Pipeline_State]int64{},
+	}
+
+	serviceStates := map[string]healthcheckPB.HealthCheckResponse_ServingStatus{}
+
+	for _, kv := range kvs {
+		// only resource state keys, i.e., resources/<uid>/types/<type>
+		parts := strings.Split(string(kv.Key), "/")
+		if len(parts) != 4 || parts[2] != "types" {
+			continue
+		}
+
+		state, err := strconv.ParseInt(string(kv.Value), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		switch parts[3] {
+		case util.RESOURCE_TYPE_MODEL:
+			systemHealth.Models[modelPB.Model_State(state)]++
+		case util.RESOURCE_TYPE_SOURCE_CONNECTOR:
+			systemHealth.SourceConnectors[connectorPB.Connector_State(state)]++
+		case util.RESOURCE_TYPE_DESTINATION_CONNECTOR:
+			systemHealth.DestinationConnectors[connectorPB.Connector_State(state)]++
+		case util.RESOURCE_TYPE_PIPELINE:
+			systemHealth.Pipelines[pipelinePB.Pipeline_State(state)]++
+		case util.RESOURCE_TYPE_SERVICE:
+			serviceStates[parts[1]] = healthcheckPB.HealthCheckResponse_ServingStatus(state)
+		}
+	}
+
+	systemHealth.Status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING
+	if err := s.CheckHealth(ctx); err != nil {
+		systemHealth.Status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING
+	}
+
+	// the connections to a backend failing tell it is down before the next
+	// probe does
+	var connectivityStates map[string]connectivity.State
+	if s.clients != nil {
+		connectivityStates = s.clients.States()
+	}
+
+	// report the configured services only, in the configured order
+	for _, backendService := range config.Config.BackendServices {
+		state, ok := serviceStates[backendService.Name]
+		if !ok {
+			state = healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED
+		}
+		if connectivityState, ok := connectivityStates[backendService.Name]; ok && connectivityState == connectivity.TransientFailure {
+			state = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING
+		}
+
+		systemHealth.Services = append(systemHealth.Services, &controllerPB.Resource{
+			ResourcePermalink: util.ConvertServiceToResourceName(backendService.Name),
+			State: &controllerPB.Resource_BackendState{
+				BackendState: state,
+			},
+		})
+
+		switch {
+		case state == healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING:
+			systemHealth.Status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING
+		case state == healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED &&
+			systemHealth.Status == healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING:
+			systemHealth.Status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_UNSPECIFIED
+		}
+	}
+
+	return &systemHealth, nil
+}